@@ -1,8 +1,11 @@
 package main
 
 import (
+	"os"
 	"fmt"
+	"flag"
 	"time"
+	"strings"
 
 	"github.com/rivo/tview"
 	"github.com/gdamore/tcell/v2"
@@ -17,8 +20,9 @@ type Assets struct {
 	head           string
 	headBlink      string
 	happyHead      string
+	pettedHead     string
 	body           string
-	encouragements []string
+	encouragements []utils.Encouragement
 }
 
 // Load all ASCII and text assets
@@ -28,10 +32,13 @@ func loadAssets() (*Assets, error) {
 		return nil, fmt.Errorf("could not load encouragements: %v", err)
 	}
 
+	happyHead := utils.LoadASCII(utils.BasePath + "/expressions/-happy")
+
 	return &Assets{
 		head:           utils.LoadASCII(utils.BasePath + "/expressions/neutral"),
 		headBlink:      utils.LoadASCII(utils.BasePath + "/expressions/neutral-blink"),
-		happyHead:      utils.LoadASCII(utils.BasePath + "/expressions/-happy"),
+		happyHead:      happyHead,
+		pettedHead:     utils.LoadOptionalASCII(utils.BasePath+"/expressions/petted", happyHead),
 		body:           utils.LoadASCII(utils.BasePath + "/clothes/hoodie"),
 		encouragements: encouragements,
 	}, nil
@@ -47,6 +54,7 @@ type UI struct {
 	waifuArt     *tview.TextView
 	chatBox      *tview.TextView
 	grid         *tview.Grid
+	pages        *tview.Pages
 	stopBlink   chan bool
 }
 
@@ -81,127 +89,446 @@ func createUI(assets *Assets) *UI {
 		AddItem(waifuArt,     0, 1, 1, 1, 0, 75, false).
 		AddItem(chatBox,      1, 1, 1, 1, 0, 0,  false)
 
-	return &UI{app, actionSpace, happinessBar, waifuArt, chatBox, grid, make(chan bool)}
+	// Pages let modals (help, command palette) float over the grid
+	pages := tview.NewPages().
+		AddPage(utils.MainPage, grid, true, true)
+
+	return &UI{app, actionSpace, happinessBar, waifuArt, chatBox, grid, pages, make(chan bool)}
 }
 
 // ==============================
-// ACTION SPACE SETUP
+// ACTIONS
 // ==============================
-func setupActionSpace(ui *UI, assets *Assets, encourageLocked *bool, currentBody *string, keys utils.KeyBindings, waifuName string) {
-	ui.actionSpace.AddItem("Encourage", "  Get a nice message.", rune(keys.Encourage[0]), func() {
-		if !*encourageLocked {
+
+// registerActions declares every action once; the list and the shortcuts are built from them
+func registerActions(ui *UI, assets *Assets, encourageLocked, petLocked *bool, currentBody *string, settings *utils.Settings) {
+	waifuName := settings.Name
+	gridUnlocked := func() bool { return !utils.LockGridChanges }
+
+	utils.RegisterAction(utils.Action{
+		ID:          "encourage",
+		Label:       "Encourage",
+		Description: "Get a nice message.",
+		DefaultKey:  utils.KeyBinding{"1"},
+		Enabled:     func() bool { return !*encourageLocked },
+		Handler: func() {
 			*encourageLocked = true
 			utils.Encourage(ui.app, ui.waifuArt, ui.chatBox,
 				assets.head, assets.happyHead, *currentBody, waifuName,
 				assets.encouragements, 1*time.Second,
 				func() { *encourageLocked = false })
-		}
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "talk",
+		Label:       "Talk",
+		Description: "Say something to her.",
+		DefaultKey:  utils.KeyBinding{"t"},
+		Enabled:     gridUnlocked,
+		Handler: func() {
+			utils.Talk(ui.app, ui.grid, ui.actionSpace, ui.waifuArt, ui.chatBox,
+				&assets.head, assets.happyHead, waifuName, currentBody, 2*time.Second)
+		},
 	})
 
-	ui.actionSpace.AddItem("Gift", "  Give a gift.", rune(keys.Gift[0]), func() {
-		if !utils.LockGridChanges {
+	utils.RegisterAction(utils.Action{
+		ID:          "play",
+		Label:       "Play",
+		Description: "Play a mini-game together.",
+		DefaultKey:  utils.KeyBinding{"p"},
+		Enabled:     gridUnlocked,
+		Handler: func() {
+			utils.PlayMenu(ui.app, ui.grid, ui.actionSpace, ui.waifuArt, ui.chatBox,
+				assets.head, assets.happyHead, waifuName, currentBody)
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "focus",
+		Label:       "Focus",
+		Description: "Start or stop the focus timer.",
+		DefaultKey:  utils.KeyBinding{"f"},
+		Handler: func() {
+			utils.ToggleFocus(ui.waifuArt, ui.chatBox, &assets.head, assets.happyHead, waifuName, currentBody)
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "gift",
+		Label:       "Gift",
+		Description: "Give a gift.",
+		DefaultKey:  utils.KeyBinding{"2"},
+		Enabled:     gridUnlocked,
+		Handler: func() {
 			utils.GiftMenu(ui.app, ui.grid, ui.actionSpace, ui.waifuArt, ui.chatBox,
 				assets.head, assets.happyHead, waifuName, currentBody)
-		}
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "shop",
+		Label:       "Shop",
+		Description: "Buy gifts with coins.",
+		DefaultKey:  utils.KeyBinding{"5"},
+		Enabled:     gridUnlocked,
+		Handler: func() {
+			utils.ShopMenu(ui.app, ui.grid, ui.actionSpace, ui.chatBox)
+		},
 	})
 
-	ui.actionSpace.AddItem("Dress Up", "  Change the outfit.", rune(keys.DressUp[0]), func() {
-		if !utils.LockGridChanges {
-			utils.DressUp(ui.app, ui.grid, ui.actionSpace,ui.waifuArt, ui.chatBox,
+	utils.RegisterAction(utils.Action{
+		ID:          "dressup",
+		Label:       "Dress Up",
+		Description: "Open the wardrobe.",
+		DefaultKey:  utils.KeyBinding{"3"},
+		Enabled:     gridUnlocked,
+		Handler: func() {
+			utils.DressUp(ui.app, ui.grid, ui.actionSpace, ui.waifuArt, ui.chatBox,
 				assets.head, waifuName, currentBody)
-		}
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "history",
+		Label:       "Chat History",
+		Description: "Scroll through past messages.",
+		DefaultKey:  utils.KeyBinding{"c"},
+		Handler: func() {
+			utils.ShowChatHistory(ui.app, ui.pages, settings.Keys.For(utils.FindAction("history")))
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "achievements",
+		Label:       "Achievements",
+		Description: "See what you unlocked together.",
+		DefaultKey:  utils.KeyBinding{"4"},
+		Handler: func() {
+			utils.ShowAchievements(ui.app, ui.pages, settings.Keys.For(utils.FindAction("achievements")))
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "stats",
+		Label:       "Stats",
+		Description: "Everything counted since day one.",
+		DefaultKey:  utils.KeyBinding{"6"},
+		Handler: func() {
+			utils.ShowStats(ui.app, ui.pages, settings.Keys.For(utils.FindAction("stats")))
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "events",
+		Label:       "Event Log",
+		Description: "What happened while you were around.",
+		DefaultKey:  utils.KeyBinding{"e"},
+		Handler: func() {
+			utils.ShowEventLog(ui.app, ui.pages, settings.Keys.For(utils.FindAction("events")))
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "backgroundMode",
+		Label:       "Background Mode",
+		Description: "Remove all odd TUI.",
+		DefaultKey:  utils.KeyBinding{"b"},
+		Enabled:     gridUnlocked,
+		Handler: func() {
+			utils.BackgroundMode(ui.app, ui.grid, ui.waifuArt, ui.chatBox, ui.happinessBar, ui.actionSpace, currentBody)
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "acknowledge",
+		Label:       "Acknowledge",
+		Description: "Confirm the reminder she just gave.",
+		DefaultKey:  utils.KeyBinding{"a"},
+		Hidden:      true,
+		Enabled:     utils.ReminderPending,
+		Handler: func() {
+			utils.AcknowledgeReminder(ui.chatBox, waifuName)
+		},
+	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "pet",
+		Label:       "Pet",
+		Description: "Give head pats (or click/drag over the avatar).",
+		Hidden:      true,
+		Enabled:     func() bool { return !*petLocked },
+		Handler: func() {
+			*petLocked = true
+			utils.Pet(ui.app, ui.waifuArt, ui.chatBox,
+				assets.head, assets.pettedHead, *currentBody, waifuName,
+				time.Duration(settings.PetCooldown)*time.Second,
+				func() { *petLocked = false })
+		},
 	})
 
-	ui.actionSpace.AddItem("Background Mode", "  Remove all odd TUI.", rune(keys.BackgroundMode[0]), func() {
-		utils.BackgroundMode(ui.app, ui.grid, ui.waifuArt, ui.chatBox, ui.happinessBar, ui.actionSpace, currentBody)
+	utils.RegisterAction(utils.Action{
+		ID:          "help",
+		Label:       "Help",
+		Description: "Show every action and key.",
+		DefaultKey:  utils.KeyBinding{"?"},
+		Hidden:      true,
+		Handler: func() {
+			utils.ShowHelp(ui.app, ui.pages, settings)
+		},
 	})
 
-	ui.actionSpace.AddItem("Quit", "  Exit the application.", rune(keys.Quit[0]), func() {
-		ui.app.Stop()
+	utils.RegisterAction(utils.Action{
+		ID:          "commands",
+		Label:       "Command Palette",
+		Description: "Run an action, gift or outfit by name.",
+		DefaultKey:  utils.KeyBinding{":"},
+		Hidden:      true,
+		Handler: func() {
+			utils.ShowCommandPalette(ui.app, ui.pages, paletteCommands(ui, assets, currentBody, waifuName))
+		},
 	})
+
+	utils.RegisterAction(utils.Action{
+		ID:          "quit",
+		Label:       "Quit",
+		Description: "Exit the application.",
+		DefaultKey:  utils.KeyBinding{"q"},
+		Handler: func() {
+			ui.app.Stop()
+		},
+	})
+}
+
+// ==============================
+// ACTION SPACE SETUP
+// ==============================
+
+// actionLabel shows the bound keys next to the action name
+func actionLabel(name string, binding utils.KeyBinding) string {
+	if label := binding.Label(); label != "" {
+		return name + " " + tview.Escape("["+label+"]")
+	}
+	return name
+}
+
+// paletteCommands lists every action, gift and outfit for the command palette
+func paletteCommands(ui *UI, assets *Assets, currentBody *string, waifuName string) []utils.Command {
+	var commands []utils.Command
+
+	for _, action := range utils.Actions() {
+		a := action
+		if a.ID == "commands" {
+			continue
+		}
+		commands = append(commands, utils.Command{
+			Name:        a.ID,
+			Description: a.Label,
+			Run:         func() { a.Run() },
+		})
+	}
+
+	// Vim style quit
+	commands = append(commands, utils.Command{
+		Name:        "q",
+		Description: "Quit",
+		Run:         func() { ui.app.Stop() },
+	})
+
+	for _, g := range utils.OwnedGifts() {
+		gift := g
+		commands = append(commands, utils.Command{
+			Name:        "gift " + gift.Name,
+			Description: fmt.Sprintf("x%d, %+d happiness", utils.InventoryCount(gift.Name), gift.Effect()),
+			Run: func() {
+				utils.GiveGift(ui.waifuArt, ui.chatBox,
+					assets.head, assets.happyHead, waifuName, currentBody, gift)
+			},
+		})
+	}
+
+	for _, g := range utils.AvailableGifts() {
+		gift := g
+		commands = append(commands, utils.Command{
+			Name:        "buy " + gift.Name,
+			Description: fmt.Sprintf("%d¢", gift.Cost()),
+			Run: func() {
+				if !utils.SpendCoins(gift.Cost()) {
+					utils.SetChat(ui.chatBox, fmt.Sprintf("Not enough coins for the %s.", gift.Name))
+					return
+				}
+				utils.AddToInventory(gift.Name, 1)
+				utils.SetChat(ui.chatBox, fmt.Sprintf("Bought a %s! You have %d¢ left.", gift.Name, utils.Coins()))
+			},
+		})
+	}
+
+	for _, n := range utils.OutfitNames() {
+		name := n
+		commands = append(commands, utils.Command{
+			Name:        "dress " + name,
+			Description: "Change the outfit",
+			Run: func() {
+				utils.ChangeOutfit(ui.waifuArt, ui.chatBox, assets.head, waifuName, currentBody, name)
+			},
+		})
+	}
+
+	return commands
+}
+
+func setupActionSpace(ui *UI, keys utils.KeyBindings) {
+	for _, action := range utils.Actions() {
+		a := action
+		if a.Hidden {
+			continue
+		}
+		ui.actionSpace.AddItem(actionLabel(a.Label, keys.For(a)), "  "+a.Description, 0, func() {
+			a.Run()
+		})
+	}
 }
 
 // ==============================
 // GLOBAL KEYS
 // ==============================
-func setGlobalKeys(ui *UI, assets *Assets, encourageLocked *bool, currentBody *string, keys utils.KeyBindings, isVimNavigation bool, waifuName string) {
+func setGlobalKeys(ui *UI, keys utils.KeyBindings, vim *utils.VimNavigator) {
 	ui.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		
-		// Main keys for actions
-		switch event.Rune() {
-		case rune(keys.Encourage[0]):
-			if !*encourageLocked {
-				*encourageLocked = true
-				utils.Encourage(ui.app, ui.waifuArt, ui.chatBox,
-					assets.head, assets.happyHead, *currentBody, waifuName,
-					assets.encouragements, 1*time.Second,
-					func() { *encourageLocked = false })
-			}
-			return nil
-		case rune(keys.Gift[0]):
-			if !utils.LockGridChanges {
-				utils.GiftMenu(ui.app, ui.grid, ui.actionSpace, ui.waifuArt, ui.chatBox,
-					assets.head, assets.happyHead, waifuName, currentBody)
-			}
-			return nil
-		case rune(keys.DressUp[0]):
-			if !utils.LockGridChanges {
-				utils.DressUp(ui.app, ui.grid, ui.actionSpace,ui.waifuArt,
-					ui.chatBox, assets.head, waifuName, currentBody)
-			}
-			return nil
-		case rune(keys.BackgroundMode[0]):
-			utils.BackgroundMode(ui.app, ui.grid, ui.waifuArt, ui.chatBox, ui.happinessBar, ui.actionSpace, currentBody)
-			return nil
-		case rune(keys.Quit[0]):
-			ui.app.Stop()
-			return nil
+		// Modals and text inputs get every key
+		if utils.OverlayOpen(ui.pages) {
+			return event
+		}
+		if _, ok := ui.app.GetFocus().(*tview.InputField); ok {
+			return event
 		}
 
-		// Vim-like navigation keys that work on any focused list
-		if isVimNavigation {
-			if focusedPrimitive := ui.app.GetFocus(); focusedPrimitive != nil {
-				// Check if the focused primitive is a list to enable vim navigation
-				if list, ok := focusedPrimitive.(*tview.List); ok {
-					switch event.Rune() {
-					case 'j':
-						// Move down in the list
-						currentItem := list.GetCurrentItem()
-						if currentItem < list.GetItemCount()-1 {
-							list.SetCurrentItem(currentItem + 1)
-						}
-						return nil
-					case 'k':
-						// Move up in the list
-						currentItem := list.GetCurrentItem()
-						if currentItem > 0 {
-							list.SetCurrentItem(currentItem - 1)
-						}
-						return nil
-					case 'l':
-						// Select the current item
-						currentIndex := list.GetCurrentItem()
-						if currentIndex >= 0 && currentIndex < list.GetItemCount() {
-							// Simulate Enter key to trigger the selected function
-							return tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
-						}
-						return nil
-					case 'h':
-						// Go back/escape
-						return tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
-					}
+		// Vim-like navigation layer that works on any focused list
+		if vim != nil {
+			if list, ok := ui.app.GetFocus().(*tview.List); ok {
+				if out, handled := vim.HandleKey(list, event); handled {
+					return out
 				}
 			}
 		}
 
+		// Main keys for actions
+		if action := utils.ActionForKey(keys, event); action != nil {
+			action.Run()
+			return nil
+		}
+
 		return event
 	})
 }
 
+// ==============================
+// MOUSE
+// ==============================
+
+// setupMouse makes clicks and drags over the avatar count as petting
+func setupMouse(ui *UI) {
+	pet := utils.FindAction("pet")
+	var lastX, lastY, stroke int
+
+	ui.waifuArt.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		x, y := event.Position()
+		switch action {
+		case tview.MouseLeftDown:
+			lastX, lastY, stroke = x, y, 0
+		case tview.MouseMove:
+			// Stroking her with the button held counts as petting too
+			if event.Buttons()&tcell.ButtonPrimary != 0 {
+				stroke += abs(x-lastX) + abs(y-lastY)
+				lastX, lastY = x, y
+				if stroke >= 6 {
+					stroke = 0
+					pet.Run()
+				}
+			}
+		case tview.MouseLeftClick, tview.MouseLeftDoubleClick:
+			pet.Run()
+		case tview.MouseScrollUp, tview.MouseScrollDown:
+			return action, event
+		}
+		// Consume the event so the art never steals the focus
+		return action, nil
+	})
+
+	// Clicking the text boxes shouldn't take the focus away from the lists either
+	keepFocus := func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action == tview.MouseLeftDown || action == tview.MouseLeftClick {
+			return action, nil
+		}
+		return action, event
+	}
+	ui.chatBox.SetMouseCapture(keepFocus)
+	ui.happinessBar.SetMouseCapture(keepFocus)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ==============================
+// SUBCOMMANDS
+// ==============================
+func runCommand(args []string, profile string) error {
+	switch args[0] {
+	case "profile":
+		return utils.RunProfileCommand(args[1:])
+	case "stats":
+		if err := utils.SetProfile(profile); err != nil {
+			return err
+		}
+		return utils.RunStatsCommand(args[1:])
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
 // ==============================
 // MAIN
 // ==============================
 func main() {
+	// ===== Select profile
+	// =====
+	profile := flag.String("profile", "", "name of the profile to use (see `cliwt profile list`)")
+	flag.Parse()
+	// Subcommands run without the TUI
+	if args := flag.Args(); len(args) > 0 {
+		if err := runCommand(args, *profile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *profile == "" {
+		picked, ok, err := utils.PickProfile()
+		if err != nil {
+			panic(err)
+		}
+		if !ok {
+			return
+		}
+		*profile = picked
+	}
+	if err := utils.SetProfile(*profile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	utils.BasePath = utils.GetBasePath()
+	utils.LoadExpressions()
+
+	// ===== Load save
+	// =====
+	save, err := utils.LoadSave()
+	if err != nil {
+		panic(err)
+	}
+	utils.Happiness = save.Happiness
+
 	// ===== Load assets
 	// =====
 	assets, err := loadAssets()
@@ -224,6 +551,7 @@ func main() {
 	utils.UIEventsChan = uiEvents
 	// Create happiness bar's variable
 	utils.HappinessBarRef = ui.happinessBar
+	utils.WaifuArtRef = ui.waifuArt
 	utils.GetHappinessBar()
 	ui.happinessBar.SetText(utils.CurrentBar)
 
@@ -249,29 +577,86 @@ func main() {
 	// Apply Waifu's name
 	ui.waifuArt.SetTitle("| " + settings.Name + " |")
 	// Apply deafult message in ChatBox
-	ui.chatBox.SetText(settings.DefaultMessage)
+	ui.chatBox.SetText(utils.PhaseMessage(settings.DefaultMessage))
 
 	// ===== Variable work
 	// =====
 	var encourageLocked bool
+	var petLocked bool
 	currentBody := assets.body
+	utils.CurrentOutfit = "hoodie"
 
 	// ===== Set functions
 	// =====
-	setupActionSpace(ui, assets, &encourageLocked, &currentBody, settings.Keys, settings.Name)
-	setGlobalKeys(ui, assets, &encourageLocked, &currentBody, settings.Keys, settings.VimNavigation, settings.Name)
+	registerActions(ui, assets, &encourageLocked, &petLocked, &currentBody, settings)
+	// A first launch writes settings.json now that every action and its default key are known
+	if err := utils.CreateSettingsFile(); err != nil {
+		panic(err)
+	}
+	setupActionSpace(ui, settings.Keys)
+	// Problems in settings.json, shown once the launch messages are out
+	warnings := append([]string(nil), settings.Warnings...)
+	var vim *utils.VimNavigator
+	if settings.VimNavigation {
+		vim, err = utils.NewVimNavigator(ui.app, ui.pages, settings, func(event *tcell.EventKey) {
+			// A lone key that wasn't followed by a motion still triggers its action
+			if action := utils.ActionForKey(settings.Keys, event); action != nil {
+				action.Run()
+			}
+		})
+		if err != nil {
+			// Vim navigation stays off until the sequence is fixed
+			warnings = append(warnings, "settings.json: " + err.Error() + " - vim navigation is off")
+		} else if conflicts := utils.VimConflicts(settings, utils.Actions()); len(conflicts) > 0 {
+			warnings = append(warnings, "Vim keys shadow: " + strings.Join(conflicts, ", ") + " - remap \"vimKeys\" in settings.json")
+		}
+	}
+	setGlobalKeys(ui, settings.Keys, vim)
+	if settings.Mouse {
+		setupMouse(ui)
+	}
 
 	// ===== Auto processes
 	// =====
+	utils.StartEarning(settings.CoinsPerMinute)
+	utils.AutoSave(time.Minute)
+	ackKey := settings.Keys.For(utils.FindAction("acknowledge")).Label()
+	if err := utils.StartReminders(ui.waifuArt, ui.chatBox, &assets.head, settings.Name, &currentBody, ackKey); err != nil {
+		utils.SetChat(ui.chatBox, err.Error())
+	}
+	utils.StartRelationship(ui.waifuArt, ui.chatBox, &assets.head, assets.happyHead, settings.Name, &currentBody)
+	utils.StartAchievements(ui.waifuArt, ui.chatBox, &assets.head, assets.happyHead, settings.Name, &currentBody)
+	utils.StartStats()
+	utils.StartWishes(ui.chatBox, settings.Name)
+	if err := utils.StartEvents(ui.waifuArt, ui.chatBox, &assets.head, settings.Name, &currentBody, settings.Keys); err != nil {
+		utils.SetChat(ui.chatBox, err.Error())
+	}
+	if err := utils.StartCalendar(ui.waifuArt, ui.chatBox, &assets.head, settings.Name, &currentBody); err != nil {
+		utils.SetChat(ui.chatBox, err.Error())
+	}
+	utils.StartStreak(ui.waifuArt, ui.chatBox, &assets.head, assets.happyHead, settings.Name, &currentBody)
 	ui.stopBlink = utils.StartBlinking(ui.app, ui.waifuArt,
 		&assets.head, &assets.headBlink, &currentBody, 5*time.Second)
 
+	// ===== Settings warnings, after the launch messages so they aren't overwritten
+	// =====
+	if unknown := utils.UnknownKeyActions(settings.Keys); len(unknown) > 0 {
+		warnings = append(warnings, "Unknown actions in \"keys\": " + strings.Join(unknown, ", ") + " - check settings.json")
+	}
+	if len(warnings) > 0 {
+		utils.SetChat(ui.chatBox, strings.Join(warnings, " / "))
+	}
+
 	// ===== No returns - Error handling
 	// =====
 	if err := utils.LoadClothes(utils.BasePath + "/clothes"); err != nil {
 		panic(err)
 	}
-	if err := ui.app.SetRoot(ui.grid, true).EnableMouse(false).Run(); err != nil {
+	utils.WearLastOutfit(ui.waifuArt, assets.head, &currentBody)
+	if err := ui.app.SetRoot(ui.pages, true).EnableMouse(settings.Mouse).Run(); err != nil {
+		panic(err)
+	}
+	if err := utils.WriteSave(); err != nil {
 		panic(err)
 	}
 	if err := utils.CreatePaletteFile(); err != nil {
@@ -0,0 +1,141 @@
+package utils
+
+import (
+    "fmt"
+    "os"
+    "encoding/json"
+    "path/filepath"
+)
+
+// ==============================
+// ACHIEVEMENTS STRUCT
+// ==============================
+type Achievement struct {
+    ID          string `json:"id"`
+    Name        string `json:"name"`
+    Description string `json:"description"`
+    Stat        string `json:"stat"`             // counter it follows, see AchievementStats
+    Goal        int    `json:"goal"`             // value of the stat that unlocks it ("outfits": 0 = every outfit)
+    Secret      bool   `json:"secret,omitempty"` // name and description hidden until unlocked
+}
+
+type AchievementsFile struct {
+    Achievements []Achievement `json:"achievements"`
+}
+
+// AchievementStats are the counters achievements can follow
+var AchievementStats = map[string]string{
+    "encouragements":      "encouragements received",
+    "gifts":               "gifts given",
+    "outfits":             "different outfits worn",
+    "streak":              "best daily streak",
+    "maxHappinessMinutes": "minutes in a row at full happiness",
+    "happiness":           "happiness given in total",
+    "games":               "mini-games played",
+    "focus":               "focus sessions completed",
+    "talks":               "messages sent in Talk",
+    "checkIns":            "daily check-ins",
+    "outfitsChanged":      "outfit changes",
+    "sessions":            "times the app was opened",
+    "wishes":              "wishes fulfilled",
+}
+
+var cachedAchievements *AchievementsFile
+
+// ==============================
+// DEFAULT ACHIEVEMENTS
+// ==============================
+func DefaultAchievements() *AchievementsFile {
+    return &AchievementsFile{
+        Achievements: []Achievement{
+            {ID: "first-gift", Name: "First Gift", Description: "Give her a gift.", Stat: "gifts", Goal: 1},
+            {ID: "generous", Name: "Generous", Description: "Give 25 gifts.", Stat: "gifts", Goal: 25},
+            {ID: "cheerleader", Name: "Cheerleader", Description: "Receive 100 encouragements.", Stat: "encouragements", Goal: 100},
+            {ID: "blissful-hour", Name: "Blissful Hour", Description: "Keep her happiness full for an hour.", Stat: "maxHappinessMinutes", Goal: 60},
+            {ID: "fashionista", Name: "Fashionista", Description: "Wear every outfit.", Stat: "outfits", Goal: 0},
+            {ID: "faithful", Name: "Faithful", Description: "Check in 7 days in a row.", Stat: "streak", Goal: 7},
+            {ID: "devoted", Name: "Devoted", Description: "Check in 30 days in a row.", Stat: "streak", Goal: 30, Secret: true},
+            {ID: "sunshine", Name: "Sunshine", Description: "Give 10000 happiness.", Stat: "happiness", Goal: 10000},
+            {ID: "player", Name: "Player Two", Description: "Play 10 mini-games.", Stat: "games", Goal: 10},
+            {ID: "deep-focus", Name: "Deep Focus", Description: "Complete 10 focus sessions.", Stat: "focus", Goal: 10},
+            {ID: "wish-granter", Name: "Wish Granter", Description: "Fulfill 10 of her wishes.", Stat: "wishes", Goal: 10},
+            {ID: "chatterbox", Name: "Chatterbox", Description: "Say 50 things in Talk.", Stat: "talks", Goal: 50},
+        },
+    }
+}
+
+// ==============================
+// FILE CREATION
+// ==============================
+func CreateAchievementsFile() error {
+    configDir := ConfigDir()
+    if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+        return fmt.Errorf("failed to create config directory: %w", err)
+    }
+
+    achievementsPath := filepath.Join(configDir, "achievements.json")
+
+    if _, err := os.Stat(achievementsPath); err == nil {
+        return nil
+    }
+
+    file, err := os.Create(achievementsPath)
+    if err != nil {
+        return fmt.Errorf("failed to create achievements file: %w", err)
+    }
+    defer file.Close()
+
+    encoder := json.NewEncoder(file)
+    encoder.SetIndent("", "  ")
+    if err := encoder.Encode(DefaultAchievements()); err != nil {
+        return fmt.Errorf("failed to write default achievements: %w", err)
+    }
+
+    return nil
+}
+
+// ==============================
+// LOAD ACHIEVEMENTS
+// ==============================
+func LoadAchievements() (*AchievementsFile, error) {
+    if cachedAchievements != nil {
+        return cachedAchievements, nil
+    }
+
+    configDir := ConfigDir()
+    achievementsPath := filepath.Join(configDir, "achievements.json")
+
+    if _, err := os.Stat(achievementsPath); os.IsNotExist(err) {
+        if err := CreateAchievementsFile(); err != nil {
+            return nil, err
+        }
+    }
+
+    file, err := os.Open(achievementsPath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open achievements file: %w", err)
+    }
+    defer file.Close()
+
+    var af AchievementsFile
+    if err := json.NewDecoder(file).Decode(&af); err != nil {
+        // fallback to default if JSON broken
+        af = *DefaultAchievements()
+    }
+
+    // Achievements without an id, with an unknown stat or without a goal are skipped
+    valid := af.Achievements[:0]
+    for _, a := range af.Achievements {
+        if _, ok := AchievementStats[a.Stat]; !ok || a.ID == "" {
+            continue
+        }
+        if a.Goal < 0 || (a.Goal == 0 && a.Stat != "outfits") {
+            continue
+        }
+        valid = append(valid, a)
+    }
+    af.Achievements = valid
+
+    cachedAchievements = &af
+    return cachedAchievements, nil
+}
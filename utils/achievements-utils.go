@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// ACHIEVEMENTS
+// ==============================
+
+// achievementNotify shows an unlock, set by StartAchievements (UI goroutine only)
+var achievementNotify func(a Achievement)
+
+// TrackStat adds `n` to a counter followed by achievements
+func TrackStat(stat string, n int) {
+	saveMutex.Lock()
+	s := currentSave()
+	if s.Stats == nil {
+		s.Stats = map[string]int{}
+	}
+	s.Stats[stat] += n
+	saveMutex.Unlock()
+
+	AddXP(statXP[stat] * n)
+	checkAchievements()
+}
+
+// recordStatMax raises a counter to `value` if it is higher (records like "maxHappinessMinutes")
+func recordStatMax(stat string, value int) {
+	saveMutex.Lock()
+	s := currentSave()
+	if s.Stats == nil {
+		s.Stats = map[string]int{}
+	}
+	if value <= s.Stats[stat] {
+		saveMutex.Unlock()
+		return
+	}
+	s.Stats[stat] = value
+	saveMutex.Unlock()
+
+	checkAchievements()
+}
+
+// trackOutfit remembers that an outfit was worn at least once
+func trackOutfit(name string) {
+	saveMutex.Lock()
+	s := currentSave()
+	if slices.Contains(s.OutfitsWorn, name) {
+		saveMutex.Unlock()
+		return
+	}
+	s.OutfitsWorn = append(s.OutfitsWorn, name)
+	saveMutex.Unlock()
+
+	checkAchievements()
+}
+
+// statValue returns the value of a stat; callers must hold saveMutex
+func statValue(s *SaveState, stat string) int {
+	switch stat {
+	case "outfits":
+		return len(s.OutfitsWorn)
+	case "streak":
+		return s.BestStreak
+	}
+	return s.Stats[stat]
+}
+
+// achievementGoal returns the value to reach; 0 means it can't be reached yet
+func achievementGoal(a Achievement) int {
+	if a.Stat == "outfits" && a.Goal == 0 {
+		return len(clothesCache)
+	}
+	return a.Goal
+}
+
+// checkAchievements unlocks every achievement whose goal was reached and shows them
+func checkAchievements() {
+	af, err := LoadAchievements()
+	if err != nil {
+		return
+	}
+
+	var unlocked []Achievement
+	saveMutex.Lock()
+	s := currentSave()
+	for _, a := range af.Achievements {
+		if _, done := s.Achievements[a.ID]; done {
+			continue
+		}
+		goal := achievementGoal(a)
+		if goal <= 0 || statValue(s, a.Stat) < goal {
+			continue
+		}
+		if s.Achievements == nil {
+			s.Achievements = map[string]time.Time{}
+		}
+		s.Achievements[a.ID] = time.Now()
+		unlocked = append(unlocked, a)
+	}
+	saveMutex.Unlock()
+
+	if achievementNotify == nil || UIEventsChan == nil {
+		return
+	}
+	for _, a := range unlocked {
+		UIEventsChan <- func() {
+			achievementNotify(a)
+		}
+	}
+}
+
+// StartAchievements shows unlocks with a chat message and a happy face,
+// and follows how long happiness stays full on the tick loop
+func StartAchievements(
+	waifuArt, chatBox *tview.TextView,
+	head *string,
+	happyHead, waifuName string,
+	currentBody *string,
+) {
+	achievementNotify = func(a Achievement) {
+		SetChat(chatBox, fmt.Sprintf("%s: We unlocked an achievement! 🏆 %s - %s", waifuName, a.Name, a.Description))
+		waifuArt.SetText(happyHead + "\n" + *currentBody)
+		time.AfterFunc(3*time.Second, func() {
+			UIEventsChan <- func() {
+				waifuArt.SetText(*head + "\n" + *currentBody)
+			}
+		})
+	}
+
+	// The blink loop decays happiness right before the hooks run, so 1000 is never seen there
+	const fullHappiness = 990
+	var fullSince time.Time
+	OnTick(func(now time.Time) {
+		happinessMutex.Lock()
+		full := Happiness > fullHappiness
+		happinessMutex.Unlock()
+
+		switch {
+		case !full:
+			fullSince = time.Time{}
+		case fullSince.IsZero():
+			fullSince = now
+		default:
+			recordStatMax("maxHappinessMinutes", int(now.Sub(fullSince).Minutes()))
+		}
+	})
+
+	// Goals reached before the achievement existed in achievements.json
+	checkAchievements()
+}
+
+// ==============================
+// ACHIEVEMENTS PANEL
+// ==============================
+
+// progressBar draws `value` out of `goal` like the happiness bar
+func progressBar(value, goal, width int) string {
+	filled := 0
+	if goal > 0 {
+		filled = min(value, goal) * width / goal
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// ShowAchievements lists the unlocked achievements, then the locked ones with their progress
+func ShowAchievements(app *tview.Application, pages *tview.Pages, toggle KeyBinding) {
+	if OverlayOpen(pages) {
+		return
+	}
+	af, err := LoadAchievements()
+	if err != nil {
+		return
+	}
+
+	var done, locked strings.Builder
+	count := 0
+	saveMutex.Lock()
+	s := currentSave()
+	for _, a := range af.Achievements {
+		if at, ok := s.Achievements[a.ID]; ok {
+			count++
+			fmt.Fprintf(&done, "[::b]🏆 %s[::-]  %s [::d]%s[::-]\n",
+				tview.Escape(a.Name), tview.Escape(a.Description), at.Format("2 Jan 2006"))
+			continue
+		}
+		name, description := a.Name, a.Description
+		if a.Secret {
+			name, description = "???", "Secret achievement."
+		}
+		goal := achievementGoal(a)
+		value := min(statValue(s, a.Stat), goal)
+		fmt.Fprintf(&locked, "🔒 %s  %s\n   %s %d/%d\n",
+			tview.Escape(name), tview.Escape(description), progressBar(value, goal, 20), value, goal)
+	}
+	saveMutex.Unlock()
+
+	text := relationshipProgress() + "\n\n" + done.String()
+	if done.Len() == 0 {
+		text += "Nothing unlocked yet.\n"
+	}
+	text += "\n" + locked.String()
+
+	title := fmt.Sprintf("| Achievements %d/%d (Esc) |", count, len(af.Achievements))
+	showTextModal(app, pages, "achievements", title, text, toggle, 80, 24)
+}
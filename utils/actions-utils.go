@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ==============================
+// ACTION REGISTRY
+// ==============================
+
+// Action is one interaction of the app. The Action Space list, the global
+// shortcuts, the help overlay and the command palette are all built from these.
+type Action struct {
+	ID          string      // Stable id, also the key name in settings.json "keys"
+	Label       string      // Name shown in the Action Space
+	Description string      // Short line shown under the label
+	DefaultKey  KeyBinding  // Used when settings.json doesn't bind the action
+	Hidden      bool        // Not listed in the Action Space (still has keys and commands)
+	Enabled     func() bool // Optional; the action is ignored while it returns false
+	Handler     func()
+}
+
+var actionRegistry []*Action
+
+// RegisterAction adds an action; actions are listed in registration order
+func RegisterAction(a Action) {
+	for i, existing := range actionRegistry {
+		if existing.ID == a.ID {
+			actionRegistry[i] = &a
+			return
+		}
+	}
+	actionRegistry = append(actionRegistry, &a)
+}
+
+// Actions returns every registered action
+func Actions() []*Action {
+	return actionRegistry
+}
+
+// FindAction returns the action with the given id, or nil
+func FindAction(id string) *Action {
+	for _, a := range actionRegistry {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// IsEnabled reports whether the action can run right now
+func (a *Action) IsEnabled() bool {
+	return a.Enabled == nil || a.Enabled()
+}
+
+// Run calls the handler if the action is enabled
+func (a *Action) Run() bool {
+	if !a.IsEnabled() || a.Handler == nil {
+		return false
+	}
+	a.Handler()
+	return true
+}
+
+// ==============================
+// ACTION KEYS
+// ==============================
+
+// For returns the keys bound to the action, falling back to its default key
+func (k KeyBindings) For(a *Action) KeyBinding {
+	if binding, ok := k[a.ID]; ok {
+		return binding
+	}
+	return a.DefaultKey
+}
+
+// DefaultKeyBindings returns the default key of every registered action, as written in a new settings.json
+func DefaultKeyBindings() KeyBindings {
+	keys := KeyBindings{}
+	for _, a := range actionRegistry {
+		keys[a.ID] = a.DefaultKey
+	}
+	return keys
+}
+
+// UnknownKeyActions returns the ids in `keys` that no registered action has, sorted
+func UnknownKeyActions(keys KeyBindings) []string {
+	var unknown []string
+	for id := range keys {
+		if FindAction(id) == nil {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// ActionForKey returns the action bound to the event, or nil
+func ActionForKey(keys KeyBindings, event *tcell.EventKey) *Action {
+	for _, a := range actionRegistry {
+		if keys.For(a).Matches(event) {
+			return a
+		}
+	}
+	return nil
+}
@@ -10,8 +10,8 @@ import (
 
 // Reference to the channel for UI updates
 var UIEventsChan chan func()
-// Define the avatar's arts via their paths
-var BasePath = GetBasePath()
+// Define the avatar's arts via their paths (set once the profile is selected)
+var BasePath string
 
 // ==============================
 // EMBEDS
@@ -32,11 +32,32 @@ func LoadASCII(path string) string {
 	content, err := ASCIIFS.ReadFile(path)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to load %s: %v", path, err))
-		return ""
 	}
 	return string(content)
 }
 
+// LoadOptionalASCII loads ASCII art if the avatar has it, otherwise returns `fallback`
+func LoadOptionalASCII(path, fallback string) string {
+	content, err := ASCIIFS.ReadFile(path)
+	if err != nil {
+		return fallback
+	}
+	return string(content)
+}
+
+// ==============================
+// TICK HOOKS
+// ==============================
+
+// Functions called on every tick of the blinking loop (from its goroutine)
+var tickHooks []func(now time.Time)
+
+// OnTick registers a function for the main loop so features don't need their own timers.
+// Hooks run outside the UI goroutine: UI changes must go through UIEventsChan.
+func OnTick(hook func(now time.Time)) {
+	tickHooks = append(tickHooks, hook)
+}
+
 // ==============================
 // BLINKING WAIFU
 // ==============================
@@ -60,6 +81,11 @@ func StartBlinking(app *tview.Application, waifuArt *tview.TextView,
 			case <-ticker.C:
 				// Decrease Happiness
 				DecreaseHappiness(1)
+				// Everything else that lives on the loop
+				now := time.Now()
+				for _, hook := range tickHooks {
+					hook(now)
+				}
 				// Show blink frame
 				blinkText := *blinkHead + "\n" + *body
 				if blinkText != last && UIEventsChan != nil {
@@ -0,0 +1,141 @@
+package utils
+
+import (
+    "fmt"
+    "os"
+    "time"
+    "encoding/json"
+    "path/filepath"
+)
+
+// ==============================
+// CALENDAR STRUCT
+// ==============================
+type SpecialDay struct {
+    Date string `json:"date"`           // "MM-DD"
+    Name string `json:"name"`           // holiday, or whose birthday it is ({user}, {name}...)
+    Line string `json:"line,omitempty"` // said once that day, with {variables}
+}
+
+type CalendarFile struct {
+    Greetings       map[string][]string `json:"greetings"`       // phase -> lines said when it begins
+    DefaultMessages map[string]string   `json:"defaultMessages"` // phase -> chat box text at launch, instead of "defaultMessage"
+    Weekend         []string            `json:"weekend"`         // said once on Saturdays and Sundays
+    Holidays        []SpecialDay        `json:"holidays"`
+    Birthdays       []SpecialDay        `json:"birthdays"`
+    Pajamas         string              `json:"pajamas"`     // outfit worn at night, "" to disable
+    SleepyFrom      int                 `json:"sleepyFrom"`  // hour she starts looking sleepy
+    SleepyUntil     int                 `json:"sleepyUntil"` // hour she stops
+}
+
+var cachedCalendar *CalendarFile
+
+// ==============================
+// DEFAULT CALENDAR
+// ==============================
+func DefaultCalendar() *CalendarFile {
+    return &CalendarFile{
+        Greetings: map[string][]string{
+            "morning": {"Good morning, {user}! Did you sleep well?", "Morning~ Let's make today a nice one."},
+            "day":     {"Good afternoon, {user}! Don't forget to eat something.", "Halfway through the day already!"},
+            "evening": {"Good evening, {user}. How was your day?", "The sun is setting... you did great today."},
+            "night":   {"It's getting late, {user}... don't stay up too long.", "Night time already? Time flies with you."},
+        },
+        DefaultMessages: map[string]string{}, // empty: "defaultMessage" of settings.json
+        Weekend: []string{
+            "It's {day}! No work today, right? Let's relax together.",
+            "Weekend~ Let's take it slow today, {user}.",
+        },
+        Holidays: []SpecialDay{
+            {Date: "01-01", Name: "New Year", Line: "Happy New Year, {user}! Let's make this one great ♥"},
+            {Date: "02-14", Name: "Valentine's Day", Line: "Happy Valentine's Day... you're my valentine, right?"},
+            {Date: "10-31", Name: "Halloween", Line: "Happy Halloween! Trick or treat, {user}~"},
+            {Date: "12-25", Name: "Christmas", Line: "Merry Christmas, {user}! ♥"},
+        },
+        Birthdays:   []SpecialDay{},
+        Pajamas:     "pajamas",
+        SleepyFrom:  0,
+        SleepyUntil: 5,
+    }
+}
+
+// ==============================
+// FILE CREATION
+// ==============================
+func CreateCalendarFile() error {
+    configDir := ConfigDir()
+    if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+        return fmt.Errorf("failed to create config directory: %w", err)
+    }
+
+    calendarPath := filepath.Join(configDir, "calendar.json")
+
+    if _, err := os.Stat(calendarPath); err == nil {
+        return nil
+    }
+
+    file, err := os.Create(calendarPath)
+    if err != nil {
+        return fmt.Errorf("failed to create calendar file: %w", err)
+    }
+    defer file.Close()
+
+    encoder := json.NewEncoder(file)
+    encoder.SetIndent("", "  ")
+    if err := encoder.Encode(DefaultCalendar()); err != nil {
+        return fmt.Errorf("failed to write default calendar: %w", err)
+    }
+
+    return nil
+}
+
+// ==============================
+// LOAD CALENDAR
+// ==============================
+func LoadCalendar() (*CalendarFile, error) {
+    if cachedCalendar != nil {
+        return cachedCalendar, nil
+    }
+
+    configDir := ConfigDir()
+    calendarPath := filepath.Join(configDir, "calendar.json")
+
+    if _, err := os.Stat(calendarPath); os.IsNotExist(err) {
+        if err := CreateCalendarFile(); err != nil {
+            return nil, err
+        }
+    }
+
+    file, err := os.Open(calendarPath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open calendar file: %w", err)
+    }
+    defer file.Close()
+
+    var cf CalendarFile
+    if err := json.NewDecoder(file).Decode(&cf); err != nil {
+        // fallback to default if JSON broken
+        cf = *DefaultCalendar()
+    }
+    if cf.SleepyFrom < 0 || cf.SleepyFrom > 23 || cf.SleepyUntil < 0 || cf.SleepyUntil > 23 {
+        cf.SleepyFrom, cf.SleepyUntil = DefaultCalendar().SleepyFrom, DefaultCalendar().SleepyUntil
+    }
+
+    // Days that aren't "MM-DD" are skipped
+    cf.Holidays = validSpecialDays(cf.Holidays)
+    cf.Birthdays = validSpecialDays(cf.Birthdays)
+
+    cachedCalendar = &cf
+    return cachedCalendar, nil
+}
+
+func validSpecialDays(days []SpecialDay) []SpecialDay {
+    valid := days[:0]
+    for _, d := range days {
+        if _, err := time.Parse("01-02", d.Date); err != nil {
+            continue
+        }
+        valid = append(valid, d)
+    }
+    return valid
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"time"
+	"math/rand"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// TIME OF DAY AND CALENDAR
+// ==============================
+
+// Phase returns the part of the day used by calendar.json: "morning", "day", "evening" or "night"
+func Phase(t time.Time) string {
+	if p := TimeOfDay(t); p != "afternoon" {
+		return p
+	}
+	return "day"
+}
+
+// sleepyHour reports whether `hour` is in [from, until), wrapping around midnight
+func sleepyHour(hour, from, until int) bool {
+	if from <= until {
+		return hour >= from && hour < until
+	}
+	return hour >= from || hour < until
+}
+
+// PhaseMessage is the chat box text at launch: the phase one of calendar.json, or `fallback`
+func PhaseMessage(fallback string) string {
+	cf, err := LoadCalendar()
+	if err != nil {
+		return fallback
+	}
+	if msg := cf.DefaultMessages[Phase(time.Now())]; msg != "" {
+		return RenderTemplate(msg, CurrentContext())
+	}
+	return fallback
+}
+
+// specialLine returns the line of a holiday or birthday today, else a weekend line
+func specialLine(cf *CalendarFile, now time.Time) string {
+	today := now.Format("01-02")
+	for _, d := range cf.Holidays {
+		if d.Date == today && d.Line != "" {
+			return d.Line
+		}
+	}
+	for _, d := range cf.Birthdays {
+		if d.Date != today {
+			continue
+		}
+		if d.Line != "" {
+			return d.Line
+		}
+		return "Happy birthday, " + d.Name + "! ♥"
+	}
+	if wd := now.Weekday(); (wd == time.Saturday || wd == time.Sunday) && len(cf.Weekend) > 0 {
+		return cf.Weekend[rand.Intn(len(cf.Weekend))]
+	}
+	return ""
+}
+
+// takeSpecialDay reports whether today's special line wasn't said yet, and marks it said
+func takeSpecialDay(now time.Time) bool {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	today := now.Format("2006-01-02")
+	if s.LastSpecialDay == today {
+		return false
+	}
+	s.LastSpecialDay = today
+	return true
+}
+
+// setSleepy switches the content face for a sleepy one
+func setSleepy(on bool) {
+	happinessMutex.Lock()
+	defer happinessMutex.Unlock()
+
+	if sleepyTime == on {
+		return
+	}
+	sleepyTime = on
+	GetHappinessBar()
+}
+
+// StartCalendar follows the phases of the day on the tick loop: greetings when a phase begins,
+// pajamas at night (if the outfit exists), a sleepy face late at night,
+// and a line once a day on weekends, holidays and birthdays.
+func StartCalendar(
+	waifuArt, chatBox *tview.TextView,
+	head *string,
+	waifuName string,
+	currentBody *string,
+) error {
+	cf, err := LoadCalendar()
+	if err != nil {
+		return err
+	}
+
+	say := func(line string) {
+		text := waifuName + ": " + RenderTemplate(line, CurrentContext())
+		UIEventsChan <- func() {
+			SetChat(chatBox, text)
+		}
+	}
+
+	phase := Phase(time.Now())
+	var beforePajamas string // outfit to put back on in the morning, used on the UI goroutine
+	dressedTonight := false
+
+	setSleepy(sleepyHour(time.Now().Hour(), cf.SleepyFrom, cf.SleepyUntil))
+
+	OnTick(func(now time.Time) {
+		setSleepy(sleepyHour(now.Hour(), cf.SleepyFrom, cf.SleepyUntil))
+
+		if current := Phase(now); current != phase {
+			phase = current
+			if lines := cf.Greetings[phase]; len(lines) > 0 {
+				say(lines[rand.Intn(len(lines))])
+			}
+		}
+
+		// Pajamas once a night, the outfit from before comes back in the morning.
+		// Both are quiet changes made on the UI goroutine, which owns the outfit.
+		switch {
+		case phase == "night" && !dressedTonight:
+			dressedTonight = true
+			if cf.Pajamas == "" {
+				break
+			}
+			line := waifuName + ": " + RenderTemplate("It's late... I'm putting on my pajamas.", CurrentContext())
+			UIEventsChan <- func() {
+				previous := CurrentOutfit
+				if previous != cf.Pajamas && wearOutfit(waifuArt, *head, currentBody, cf.Pajamas) {
+					beforePajamas = previous
+					SetChat(chatBox, line)
+				}
+			}
+		case phase != "night" && dressedTonight:
+			dressedTonight = false
+			UIEventsChan <- func() {
+				if beforePajamas != "" && CurrentOutfit == cf.Pajamas {
+					wearOutfit(waifuArt, *head, currentBody, beforePajamas)
+				}
+				beforePajamas = ""
+			}
+		}
+
+		if line := specialLine(cf, now); line != "" && takeSpecialDay(now) {
+			say(line)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// CHAT LOG
+// ==============================
+
+// ChatEntry is one message shown in the chat box
+type ChatEntry struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// history view while it is open, so new messages show up live
+var chatHistoryView *tview.TextView
+
+// SetChat shows a message in the chat box and logs it; call it from the UI goroutine.
+// With "typewriter" on, the message is typed out after the ones still being typed.
+func SetChat(chatBox *tview.TextView, text string) {
+	LogChat(text)
+
+	settings, err := LoadSettings()
+	if err != nil || !settings.Typewriter || UIEventsChan == nil {
+		chatBox.SetText(text)
+		return
+	}
+	chatQueue = append(chatQueue, chatMessage{box: chatBox, text: text})
+	if !chatTyping {
+		typeNext(time.Duration(max(settings.TypewriterSpeed, 1)) * time.Millisecond)
+	}
+}
+
+// ShowChat shows a message without logging or typing it, after the ones still being typed.
+// Meant for text replaced often, like a streamed reply: only its latest version waits in line.
+// Call it from the UI goroutine.
+func ShowChat(chatBox *tview.TextView, text string) {
+	if !chatTyping {
+		chatBox.SetText(text)
+		return
+	}
+	if n := len(chatQueue); n > 0 && chatQueue[n-1].instant && chatQueue[n-1].box == chatBox {
+		chatQueue[n-1].text = text
+		return
+	}
+	chatQueue = append(chatQueue, chatMessage{chatBox, text, true})
+}
+
+// ==============================
+// TYPEWRITER
+// ==============================
+
+// How long a finished message stays before the next queued one starts
+const chatHoldTime = 1500 * time.Millisecond
+
+type chatMessage struct {
+	box     *tview.TextView
+	text    string
+	instant bool // shown whole instead of typed (ShowChat)
+}
+
+// Only touched from the UI goroutine
+var (
+	chatQueue  []chatMessage
+	chatTyping bool
+)
+
+// typeNext reveals the next queued message one character every `delay`
+func typeNext(delay time.Duration) {
+	if len(chatQueue) == 0 {
+		chatTyping = false
+		return
+	}
+	msg := chatQueue[0]
+	chatQueue = chatQueue[1:]
+	chatTyping = true
+
+	done := func() {
+		hold := time.Duration(0)
+		if len(chatQueue) > 0 {
+			hold = chatHoldTime
+		}
+		time.AfterFunc(hold, func() {
+			UIEventsChan <- func() { typeNext(delay) }
+		})
+	}
+	if msg.instant {
+		msg.box.SetText(msg.text)
+		done()
+		return
+	}
+
+	runes := []rune(msg.text)
+	m := &mouth{view: WaifuArtRef}
+
+	var step func(n int)
+	step = func(n int) {
+		msg.box.SetText(string(runes[:n]))
+		// Open and close the mouth every few characters
+		m.set(n < len(runes) && (n/3)%2 == 0)
+
+		if n >= len(runes) {
+			done()
+			return
+		}
+		time.AfterFunc(delay, func() {
+			UIEventsChan <- func() { step(n + 1) }
+		})
+	}
+	step(min(1, len(runes)))
+}
+
+// mouth swaps the head of the avatar with the "talking" frame and back
+type mouth struct {
+	view  *tview.TextView
+	open  bool
+	base  string // avatar before the mouth opened
+	shown string // avatar with the mouth open
+}
+
+func (m *mouth) set(open bool) {
+	if m.view == nil || talking == "" || open == m.open {
+		return
+	}
+	m.open = open
+
+	current := m.view.GetText(false)
+	if open {
+		m.base = current
+		m.shown = withHead(current, talking)
+		m.view.SetText(m.shown)
+	} else if current == m.shown {
+		// Only restore if nothing else (blink, reactions) changed the avatar meanwhile
+		m.view.SetText(m.base)
+	}
+}
+
+// withHead replaces the first lines of the avatar with `head`
+func withHead(avatar, head string) string {
+	headLines := strings.Split(head, "\n")
+	lines := strings.Split(avatar, "\n")
+	if len(lines) < len(headLines) {
+		return avatar
+	}
+	return strings.Join(append(headLines, lines[len(headLines):]...), "\n")
+}
+
+// LogChat appends a message to the log kept in save.json (last "chatHistory" messages)
+func LogChat(text string) {
+	entry := ChatEntry{Time: time.Now(), Text: text}
+	limit := DefaultSettings().ChatHistory
+	if s, err := LoadSettings(); err == nil {
+		limit = max(s.ChatHistory, 0)
+	}
+
+	saveMutex.Lock()
+	s := currentSave()
+	s.ChatLog = append(s.ChatLog, entry)
+	if extra := len(s.ChatLog) - limit; extra > 0 {
+		s.ChatLog = s.ChatLog[extra:]
+	}
+	previous := len(s.ChatLog) > 1
+	var last time.Time
+	if previous {
+		last = s.ChatLog[len(s.ChatLog)-2].Time
+	}
+	saveMutex.Unlock()
+
+	if chatHistoryView != nil {
+		if !previous || !sameDay(last, entry.Time) {
+			fmt.Fprint(chatHistoryView, dayHeader(entry.Time))
+		}
+		fmt.Fprint(chatHistoryView, formatChatEntry(entry))
+		chatHistoryView.ScrollToEnd()
+	}
+}
+
+// ChatLog returns a copy of the logged messages, oldest first
+func ChatLog() []ChatEntry {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return append([]ChatEntry(nil), currentSave().ChatLog...)
+}
+
+// formatChatLog renders the log with a header before each new day
+func formatChatLog(entries []ChatEntry) string {
+	if len(entries) == 0 {
+		return "Nothing said yet.\n"
+	}
+	var b strings.Builder
+	var last time.Time
+	for _, e := range entries {
+		if !sameDay(last, e.Time) {
+			b.WriteString(dayHeader(e.Time))
+		}
+		b.WriteString(formatChatEntry(e))
+		last = e.Time
+	}
+	return b.String()
+}
+
+func formatChatEntry(e ChatEntry) string {
+	return fmt.Sprintf("[::d]%s[::-] %s\n", e.Time.Format("15:04"), tview.Escape(e.Text))
+}
+
+func dayHeader(t time.Time) string {
+	return fmt.Sprintf("[::b]── %s ──[::-]\n", t.Format("Mon 2 Jan 2006"))
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// ==============================
+// HISTORY VIEW
+// ==============================
+
+// ShowChatHistory opens the expanded, scrollable chat log
+func ShowChatHistory(app *tview.Application, pages *tview.Pages, toggle KeyBinding) {
+	if OverlayOpen(pages) {
+		return
+	}
+
+	view := showTextModal(app, pages, "history", "| Chat History (j/k, PgUp/PgDn, g/G, Esc) |", formatChatLog(ChatLog()), toggle, 80, 24)
+	view.ScrollToEnd()
+	// New messages are appended while it is open
+	chatHistoryView = view
+	view.SetBlurFunc(func() {
+		chatHistoryView = nil
+	})
+}
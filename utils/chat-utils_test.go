@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"time"
+	"testing"
+)
+
+func TestLogChatKeepsTheLastMessages(t *testing.T) {
+	loadSettingsFile(t, `{"chatHistory": 3}`)
+	previous := cachedSave
+	cachedSave = DefaultSave()
+	t.Cleanup(func() { cachedSave = previous })
+
+	for _, text := range []string{"one", "two", "three", "four"} {
+		LogChat(text)
+	}
+	log := ChatLog()
+	if len(log) != 3 || log[0].Text != "two" || log[2].Text != "four" {
+		t.Fatalf("ChatLog = %+v, want two, three, four", log)
+	}
+
+	// ChatLog hands out a copy
+	log[0].Text = "changed"
+	if ChatLog()[0].Text != "two" {
+		t.Error("editing the returned log changed the save")
+	}
+}
+
+func TestLogChatWithoutHistory(t *testing.T) {
+	loadSettingsFile(t, `{"chatHistory": -1}`)
+	previous := cachedSave
+	cachedSave = DefaultSave()
+	t.Cleanup(func() { cachedSave = previous })
+
+	LogChat("hello")
+	if log := ChatLog(); len(log) != 0 {
+		t.Errorf("ChatLog = %+v, want nothing kept", log)
+	}
+}
+
+func TestFormatChatLog(t *testing.T) {
+	monday := time.Date(2026, time.March, 9, 23, 58, 0, 0, time.Local)
+	tuesday := time.Date(2026, time.March, 10, 0, 3, 0, 0, time.Local)
+	got := formatChatLog([]ChatEntry{
+		{Time: monday, Text: "Good night"},
+		{Time: monday.Add(time.Minute), Text: "[sleepy]"},
+		{Time: tuesday, Text: "Up already?"},
+	})
+	want := "[::b]── Mon 9 Mar 2026 ──[::-]\n" +
+		"[::d]23:58[::-] Good night\n" +
+		"[::d]23:59[::-] [sleepy[]\n" +
+		"[::b]── Tue 10 Mar 2026 ──[::-]\n" +
+		"[::d]00:03[::-] Up already?\n"
+	if got != want {
+		t.Errorf("formatChatLog =\n%s\nwant\n%s", got, want)
+	}
+	if got := formatChatLog(nil); got != "Nothing said yet.\n" {
+		t.Errorf("formatChatLog(nil) = %q", got)
+	}
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"fmt"
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"net/http"
+	"encoding/json"
+)
+
+// ==============================
+// CHAT PROVIDER
+// ==============================
+
+// ChatTurn is one message of a conversation, OpenAI style ("system", "user", "assistant")
+type ChatTurn struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatProvider writes her replies. `onToken` receives the reply piece by piece
+// as it is generated; an error before the first piece means "use the rule-based lines".
+type ChatProvider interface {
+	Reply(ctx context.Context, turns []ChatTurn, onToken func(string)) error
+}
+
+// OpenAIProvider talks to an OpenAI compatible chat endpoint, like the ones of
+// llama.cpp's server or Ollama ("/v1/chat/completions")
+type OpenAIProvider struct {
+	Endpoint string
+	Model    string
+	Client   *http.Client
+}
+
+// NewChatProvider returns the provider configured in settings.json, or nil if disabled
+func NewChatProvider(s *Settings) ChatProvider {
+	if !s.ChatBot.Enabled || s.ChatBot.Endpoint == "" {
+		return nil
+	}
+	return &OpenAIProvider{
+		Endpoint: s.ChatBot.Endpoint,
+		Model:    s.ChatBot.Model,
+		Client:   &http.Client{},
+	}
+}
+
+type chatRequest struct {
+	Model    string     `json:"model,omitempty"`
+	Messages []ChatTurn `json:"messages"`
+	Stream   bool       `json:"stream"`
+}
+
+// chatChunk covers both a streamed chunk (delta) and a whole response (message)
+type chatChunk struct {
+	Choices []struct {
+		Delta   ChatTurn `json:"delta"`
+		Message ChatTurn `json:"message"`
+	} `json:"choices"`
+}
+
+// Reply streams the answer of the endpoint into onToken
+func (p *OpenAIProvider) Reply(ctx context.Context, turns []ChatTurn, onToken func(string)) error {
+	body, err := json.Marshal(chatRequest{Model: p.Model, Messages: turns, Stream: true})
+	if err != nil {
+		return fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid chat endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("chat endpoint unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("chat endpoint answered %s", resp.Status)
+	}
+
+	// Servers that ignore "stream" send the whole answer as one JSON object
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var chunk chatChunk
+		if err := json.NewDecoder(resp.Body).Decode(&chunk); err != nil {
+			return fmt.Errorf("failed to read chat response: %w", err)
+		}
+		if len(chunk.Choices) == 0 {
+			return fmt.Errorf("chat endpoint sent no answer")
+		}
+		onToken(chunk.Choices[0].Message.Content)
+		return nil
+	}
+
+	// Server-sent events: "data: {...}" lines, ending with "data: [DONE]"
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			return nil
+		}
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to read chat stream: %w", err)
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			onToken(chunk.Choices[0].Delta.Content)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read chat stream: %w", err)
+	}
+	return nil
+}
+
+// ==============================
+// PERSONA
+// ==============================
+
+// MoodName describes the happiness in words, for the persona prompt
+func MoodName(happiness int) string {
+	switch {
+	case happiness > 800:
+		return "happy and affectionate"
+	case happiness > 600:
+		return "a bit confused but fine"
+	case happiness > 300:
+		return "bored and wanting attention"
+	default:
+		return "sad and lonely"
+	}
+}
+
+// PersonaPrompt is the system message telling the model who to be
+func PersonaPrompt(ctx TemplateContext) string {
+	outfit := ctx.Outfit
+	if outfit == "" {
+		outfit = "casual clothes"
+	}
+	return fmt.Sprintf("You are %s, a cute virtual companion living in a terminal. You are talking to %s. "+
+		"You are currently %s (happiness %d%%) and wearing %s. It is %s %s. "+
+		"Answer in one or two short sentences, in character, warm and PG. Never use markdown.",
+		ctx.Name, ctx.User, MoodName(ctx.Happiness), ctx.Happiness/10, outfit,
+		ctx.Now.Weekday(), TimeOfDay(ctx.Now))
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"fmt"
+	"context"
+	"strings"
+	"testing"
+	"net/http"
+	"encoding/json"
+	"net/http/httptest"
+)
+
+// providerReply runs the provider against `handler` and returns what onToken received
+func providerReply(t *testing.T, handler http.HandlerFunc) (string, error) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	p := &OpenAIProvider{Endpoint: server.URL, Model: "test", Client: server.Client()}
+	var got strings.Builder
+	err := p.Reply(context.Background(), []ChatTurn{{Role: "user", Content: "hi"}}, func(token string) {
+		got.WriteString(token)
+	})
+	return got.String(), err
+}
+
+func TestOpenAIProviderStream(t *testing.T) {
+	var request chatRequest
+	got, err := providerReply(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("bad request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"Hi", " there", "!"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", token)
+		}
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	if err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+	if got != "Hi there!" {
+		t.Errorf("streamed %q, want %q", got, "Hi there!")
+	}
+	if !request.Stream || request.Model != "test" || len(request.Messages) != 1 {
+		t.Errorf("unexpected request %+v", request)
+	}
+}
+
+func TestOpenAIProviderPlainJSON(t *testing.T) {
+	got, err := providerReply(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Hello ♥"}}]}`)
+	})
+	if err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+	if got != "Hello ♥" {
+		t.Errorf("got %q, want %q", got, "Hello ♥")
+	}
+}
+
+func TestOpenAIProviderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"non-200 status", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+		}},
+		{"no choices", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"choices":[]}`)
+		}},
+		{"broken stream", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {oops\n\n")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := providerReply(t, tt.handler)
+			if err == nil {
+				t.Fatalf("expected an error, got reply %q", got)
+			}
+			if got != "" {
+				t.Errorf("got tokens %q before the error", got)
+			}
+		})
+	}
+}
+
+func TestOpenAIProviderUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := server.URL
+	server.Close() // nothing listens there anymore
+
+	p := &OpenAIProvider{Endpoint: endpoint}
+	called := false
+	err := p.Reply(context.Background(), nil, func(string) { called = true })
+	if err == nil || !strings.Contains(err.Error(), "unavailable") {
+		t.Errorf("got error %v, want an unavailable endpoint", err)
+	}
+	if called {
+		t.Error("onToken was called")
+	}
+}
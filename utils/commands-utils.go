@@ -2,8 +2,12 @@ package utils
 
 import (
 	"fmt"
+	"math"
 	"path"
 	"time"
+	"sync"
+	"slices"
+	"strings"
 	"math/rand"
 
 	"github.com/rivo/tview"
@@ -14,29 +18,39 @@ import (
 // ENCOURAGEMENT
 // ==============================
 
-// Encourage shows a random encouragement, swaps head for `duration`, then restores
+// Encourage shows a random encouragement, swaps head for `duration`, then restores.
+// Only lines whose conditions match now are picked; {variables} are filled in.
 func Encourage(
 	app *tview.Application,
 	waifuArt, chatBox *tview.TextView,
 	head, happyHead, body, waifuName string,
-	encouragements []string,
+	encouragements []Encouragement,
 	duration time.Duration,
 	unlockFunc func(),
 ) {
-	if len(encouragements) == 0 {
+	ctx := CurrentContext()
+	var eligible []Encouragement
+	for _, e := range encouragements {
+		if e.Conditions.Match(ctx) {
+			eligible = append(eligible, e)
+		}
+	}
+	if len(eligible) == 0 {
 		unlockFunc()
 		return
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	line := encouragements[rand.Intn(len(encouragements))]
+	picked := pickEncouragement(eligible)
+	rememberEncouragement(picked.Text)
+	line := RenderTemplate(picked.Text, ctx)
 
 	// Show happy face + message instantly
 	if UIEventsChan != nil {
 		UIEventsChan <- func() {
-			chatBox.SetText(waifuName + ": " + line)
+			SetChat(chatBox, waifuName + ": " + line)
 			waifuArt.SetText(happyHead + "\n" + body)
 			IncreaseHappiness(6)
+			TrackStat("encouragements", 1)
 		}
 	}
 
@@ -51,11 +65,106 @@ func Encourage(
 	})
 }
 
+// Said when every eligible line is disabled with weight=0
+var defaultEncouragement = Encouragement{Text: "You're doing great, {user}! ♥", Weight: 1}
+
+// pickEncouragement makes a weighted pick, skipping lines shown recently (also in
+// previous sessions) so the same line doesn't come back too soon
+func pickEncouragement(eligible []Encouragement) Encouragement {
+	// Lines with weight=0 are disabled
+	var enabled []Encouragement
+	for _, e := range eligible {
+		if e.Weight > 0 {
+			enabled = append(enabled, e)
+		}
+	}
+	if len(enabled) == 0 {
+		return defaultEncouragement
+	}
+
+	// Avoid up to half of the lines, so there is always some choice left
+	recent := map[string]bool{}
+	for _, text := range recentEncouragements(len(enabled) / 2) {
+		recent[text] = true
+	}
+
+	var candidates []Encouragement
+	for _, e := range enabled {
+		if !recent[e.Text] {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = enabled
+	}
+	return weightedEncouragement(candidates)
+}
+
+// weightedEncouragement picks a line with a chance proportional to its weight (all above 0)
+func weightedEncouragement(candidates []Encouragement) Encouragement {
+	total := 0
+	for _, e := range candidates {
+		total += e.Weight
+	}
+	n := rand.Intn(total)
+	for _, e := range candidates {
+		if n < e.Weight {
+			return e
+		}
+		n -= e.Weight
+	}
+	return candidates[len(candidates)-1]
+}
+
+// ==============================
+// PETTING
+// ==============================
+
+var petReactions = []string{
+	"Ehehe~ that tickles ♥",
+	"Mmh... more head pats, please.",
+	"H-hey! ...don't stop.",
+	"*leans into your hand*",
+	"You're so gentle today.",
+}
+
+// Pet shows a petting reaction, swaps head for `duration`, then restores
+func Pet(
+	app *tview.Application,
+	waifuArt, chatBox *tview.TextView,
+	head, pettedHead, body, waifuName string,
+	duration time.Duration,
+	unlockFunc func(),
+) {
+	line := petReactions[rand.Intn(len(petReactions))]
+
+	if UIEventsChan != nil {
+		UIEventsChan <- func() {
+			SetChat(chatBox, waifuName + ": " + line)
+			waifuArt.SetText(pettedHead + "\n" + body)
+			IncreaseHappiness(4)
+			TrackStat("pets", 1)
+		}
+	}
+
+	time.AfterFunc(duration, func() {
+		if UIEventsChan != nil {
+			UIEventsChan <- func() {
+				waifuArt.SetText(head + "\n" + body)
+				unlockFunc()
+			}
+		}
+	})
+}
+
 // ==============================
 // GIFT SYSTEM
 // ==============================
 
-var giftCache []Gift
+var (
+	giftCache []Gift
+	giftMutex sync.Mutex // The tick hooks (wishes, events) read the gifts too
+)
 
 func GiftMenu(
 	app *tview.Application,
@@ -67,51 +176,34 @@ func GiftMenu(
 ) {
 
 	// Load gifts if not cached
-	if len(giftCache) == 0 {
-		gf, err := LoadGifts()
-		if err != nil {
-			showChatMessage(chatBox, "Failed to load gifts!")
-			return
-		}
-		giftCache = gf.Gifts
+	gifts, err := loadGiftCache()
+	if err != nil {
+		showChatMessage(chatBox, "Failed to load gifts!")
+		return
 	}
 
-	if len(giftCache) == 0 {
+	if len(gifts) == 0 {
 		showChatMessage(chatBox, "No gifts available!")
 		return
 	}
 
+	// Only what is in the inventory can be given
+	owned := OwnedGifts()
+	if len(owned) == 0 {
+		showChatMessage(chatBox, "No gifts in your inventory! Buy some in the Shop.")
+		return
+	}
+
 	list := tview.NewList()
 	ApplyListPalette(cachedPalette, list)
 
-	for _, g := range giftCache {
+	for _, g := range owned {
 		gift := g
 
-		display := fmt.Sprintf("- %s (+%d)", gift.Name, gift.Happiness)
+		display := fmt.Sprintf("- %s x%d (%+d)", gift.Name, InventoryCount(gift.Name), gift.Effect())
 
 		list.AddItem(display, "", 0, func() {
-			// Show reaction
-			if UIEventsChan != nil {
-				UIEventsChan <- func() {
-					chatBox.SetText(waifuName + ": Aw, thank you for the " + gift.Name + " ♥")
-
-					// Happy head + current body (same as Encourage)
-					waifuArt.SetText(happyHead + "\n" + *currentBody)
-
-					// Apply happiness from JSON
-					IncreaseHappiness(gift.Happiness)
-				}
-			}
-
-			// Restore after 1 second
-			time.AfterFunc(1*time.Second, func() {
-				if UIEventsChan != nil {
-					UIEventsChan <- func() {
-						waifuArt.SetText(head + "\n" + *currentBody)
-					}
-				}
-			})
-
+			GiveGift(waifuArt, chatBox, head, happyHead, waifuName, currentBody, gift)
 			closeGiftMenu(app, grid, list, actionSpace)
 		})
 	}
@@ -121,24 +213,176 @@ func GiftMenu(
 		closeGiftMenu(app, grid, list, actionSpace)
 	})
 
+	// The gift list points to the Shop, which must not open on top of it
+	LockGridChanges = true
 	grid.RemoveItem(actionSpace)
 	grid.AddItem(list, 0, 0, 1, 1, 0, 0, true)
 	app.SetFocus(list)
 }
 
+// GiveGift takes the gift from the inventory, shows the reaction and applies its happiness
+func GiveGift(
+	waifuArt, chatBox *tview.TextView,
+	head, happyHead, waifuName string,
+	currentBody *string,
+	gift Gift,
+) {
+	if !TakeFromInventory(gift.Name) {
+		showChatMessage(chatBox, "You don't have a "+gift.Name+"! Buy one in the Shop.")
+		return
+	}
+
+	amount, line, face := giftReaction(gift, happyHead)
+
+	// Show reaction
+	if UIEventsChan != nil {
+		UIEventsChan <- func() {
+			if FulfillWish("gift", gift.Name) {
+				line += wishGranted
+			}
+			SetChat(chatBox, waifuName + ": " + line)
+
+			// Reaction head + current body (same as Encourage)
+			waifuArt.SetText(face + "\n" + *currentBody)
+
+			// Apply happiness from JSON (disliked gifts lower it)
+			if amount >= 0 {
+				IncreaseHappiness(amount)
+			} else {
+				DecreaseHappiness(-amount)
+			}
+			TrackStat("gifts", 1)
+			countGift(gift.Name)
+		}
+	}
+
+	// Restore after 1 second
+	time.AfterFunc(1*time.Second, func() {
+		if UIEventsChan != nil {
+			UIEventsChan <- func() {
+				waifuArt.SetText(head + "\n" + *currentBody)
+			}
+		}
+	})
+}
+
+// giftReaction works out the happiness change, the line and the face for a gift
+func giftReaction(gift Gift, happyHead string) (int, string, string) {
+	repeats := recordGift(gift.Name)
+	preference := strings.ToLower(gift.Preference)
+
+	amount := float64(gift.Effect())
+	// Diminishing returns: each repeat within the window is worth less
+	reduced := amount > 0 && repeats > 0 && repeatFactor() < 1
+	if reduced {
+		amount *= math.Pow(repeatFactor(), float64(repeats))
+	}
+
+	var line string
+	switch {
+	case reduced && preference != "dislike":
+		line = "Another " + gift.Name + "? ...thank you, again."
+	case len(gift.Reactions) > 0:
+		line = strings.ReplaceAll(gift.Reactions[rand.Intn(len(gift.Reactions))], "{gift}", gift.Name)
+	case preference == "dislike":
+		line = "Oh... a " + gift.Name + ". Thanks, I guess."
+	default:
+		line = "Aw, thank you for the " + gift.Name + " ♥"
+	}
+
+	face := happyHead
+	if preference == "dislike" {
+		face = confused
+	}
+	if gift.Expression != "" {
+		face = LoadOptionalASCII(BasePath+"/expressions/"+gift.Expression, face)
+	}
+
+	return int(math.Round(amount)), line, face
+}
+
+// recordGift logs the gift and returns how many times it was already given within the window
+func recordGift(name string) int {
+	window := 10 * time.Minute
+	giftMutex.Lock()
+	if cachedGifts != nil {
+		window = time.Duration(cachedGifts.RepeatWindow) * time.Minute
+	}
+	giftMutex.Unlock()
+	now := time.Now()
+
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	if s.RecentGifts == nil {
+		s.RecentGifts = map[string][]time.Time{}
+	}
+	// Forget everything outside of the window, for every gift
+	for gift, times := range s.RecentGifts {
+		kept := times[:0]
+		for _, t := range times {
+			if now.Sub(t) < window {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.RecentGifts, gift)
+		} else {
+			s.RecentGifts[gift] = kept
+		}
+	}
+
+	repeats := len(s.RecentGifts[name])
+	s.RecentGifts[name] = append(s.RecentGifts[name], now)
+	return repeats
+}
+
+func repeatFactor() float64 {
+	giftMutex.Lock()
+	defer giftMutex.Unlock()
+	if cachedGifts != nil {
+		return cachedGifts.RepeatFactor
+	}
+	return DefaultGifts().RepeatFactor
+}
+
+// loadGiftCache fills the gift cache from gifts.json on first use
+func loadGiftCache() ([]Gift, error) {
+	giftMutex.Lock()
+	defer giftMutex.Unlock()
+
+	if len(giftCache) == 0 {
+		gf, err := LoadGifts()
+		if err != nil {
+			return nil, err
+		}
+		giftCache = gf.Gifts
+	}
+	return giftCache, nil
+}
+
+// AvailableGifts returns the gifts from gifts.json (cached)
+func AvailableGifts() []Gift {
+	gifts, _ := loadGiftCache()
+	return gifts
+}
+
 func showChatMessage(chatBox *tview.TextView, msg string) {
 	if UIEventsChan != nil {
 		UIEventsChan <- func() {
-			chatBox.SetText(msg)
+			SetChat(chatBox, msg)
 		}
 	}
 }
 
+// closeGiftMenu restores the actionSpace in place of a menu and unlocks the grid
 func closeGiftMenu(
 	app *tview.Application,
 	grid *tview.Grid,
 	list, actionSpace *tview.List,
 ) {
+	LockGridChanges = false
 
 	grid.RemoveItem(list)
 	grid.AddItem(actionSpace, 0, 0, 1, 1, 0, 0, true)
@@ -149,56 +393,116 @@ func closeGiftMenu(
 // DRESS-UP
 // ==============================
 
+var (
+	CurrentOutfit string     // Name of the outfit being worn ({outfit} in lines), changed on the UI goroutine
+	outfitMutex   sync.Mutex // Protects CurrentOutfit against the tick hooks reading it
+)
+
+// wornOutfit reads CurrentOutfit from outside of the UI goroutine
+func wornOutfit() string {
+	outfitMutex.Lock()
+	defer outfitMutex.Unlock()
+	return CurrentOutfit
+}
+
+// setOutfit changes CurrentOutfit (UI goroutine only)
+func setOutfit(name string) {
+	outfitMutex.Lock()
+	defer outfitMutex.Unlock()
+	CurrentOutfit = name
+}
+
 var clothesCache []struct {
 	Name string
 	Data string
 }
 
-// DressUp allows the user to pick a clothes ASCII file from a scrollable list
-func DressUp(
-	app *tview.Application,
-	grid *tview.Grid,
-	actionSpace *tview.List,
+// ChangeOutfit puts on the outfit with the given name from the clothes cache
+func ChangeOutfit(
 	waifuArt, chatBox *tview.TextView,
 	head, waifuName string,
 	currentBody *string,
-) {
-	if len(clothesCache) == 0 {
+	name string,
+) bool {
+	for _, item := range clothesCache {
+		if item.Name != name || !OutfitUnlocked(name) {
+			continue
+		}
+		data := item.Data
+		rememberOutfit(name)
 		if UIEventsChan != nil {
 			UIEventsChan <- func() {
-				chatBox.SetText("No clothes found!")
+				*currentBody = data
+				setOutfit(name)
+				waifuArt.SetText(head + "\n" + *currentBody)
+				if FulfillWish("outfit", name) {
+					SetChat(chatBox, waifuName + " changed into: " + name + "." + wishGranted)
+				} else {
+					SetChat(chatBox, waifuName + " changed into: " + name)
+				}
+				IncreaseHappiness(3)
+				trackOutfit(name)
+				countOutfit(name)
+				TrackStat("outfitsChanged", 1)
 			}
 		}
-		return
+		return true
 	}
+	return false
+}
 
-	list := tview.NewList()
-	ApplyListPalette(cachedPalette, list) // Safe: the error could have occurred during the initialization
+// wearOutfit puts on an outfit quietly: no reaction, rewards, stats or wish.
+// Call it from the UI goroutine; returns false for unknown or locked outfits.
+func wearOutfit(waifuArt *tview.TextView, head string, currentBody *string, name string) bool {
+	if !OutfitUnlocked(name) {
+		return false
+	}
 	for _, item := range clothesCache {
-		display := "-" + item.Name
-		list.AddItem(display, "", 0, func() {
-			if UIEventsChan != nil {
-				UIEventsChan <- func() {
-					*currentBody = item.Data
-					waifuArt.SetText(head + "\n" + *currentBody)
-					chatBox.SetText(waifuName + " changed into: " + item.Name)
-					IncreaseHappiness(3)
-				}
-			}
+		if item.Name == name {
+			*currentBody = item.Data
+			setOutfit(name)
+			waifuArt.SetText(head + "\n" + *currentBody)
+			return true
+		}
+	}
+	return false
+}
 
-			closeDressUp(app, grid, list, actionSpace, waifuArt, head, currentBody)
-		})
+// OutfitNames returns the names of every outfit that can be worn
+func OutfitNames() []string {
+	var names []string
+	for _, item := range clothesCache {
+		if OutfitUnlocked(item.Name) {
+			names = append(names, item.Name)
+		}
 	}
+	return names
+}
 
-	list.SetBorder(true).SetTitle("| Dress Up |").SetTitleAlign(tview.AlignCenter)
-	list.SetDoneFunc(func() {
-		closeDressUp(app, grid, list, actionSpace, waifuArt, head, currentBody)
-	})
+// exclusiveOutfits is the clothes subdirectory of outfits that have to be unlocked
+const exclusiveOutfits = "exclusive/"
 
-	// Swap in the dress-up list
-	grid.RemoveItem(actionSpace)
-	grid.AddItem(list, 0, 0, 1, 1, 0, 0, true)
-	app.SetFocus(list)
+// OutfitUnlocked reports whether an outfit can be worn: exclusive ones are unlocked by rewards
+func OutfitUnlocked(name string) bool {
+	if !strings.HasPrefix(name, exclusiveOutfits) {
+		return true
+	}
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return slices.Contains(currentSave().UnlockedOutfits, name)
+}
+
+// UnlockOutfit adds an exclusive outfit to the wardrobe; returns false if it was already there
+func UnlockOutfit(name string) bool {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	if slices.Contains(s.UnlockedOutfits, name) {
+		return false
+	}
+	s.UnlockedOutfits = append(s.UnlockedOutfits, name)
+	return true
 }
 
 // scanASCIIFiles recursively scans directory and returns paths and display names
@@ -259,22 +563,6 @@ func LoadClothes(dir string) error {
 	return nil
 }
 
-// closeDressUp restores the actionSpace and restarts blinking
-func closeDressUp(
-	app *tview.Application,
-	grid *tview.Grid,
-	list *tview.List,
-	actionSpace *tview.List,
-	waifuArt *tview.TextView,
-	head string,
-	currentBody *string,
-) {
-
-	grid.RemoveItem(list)
-	grid.AddItem(actionSpace, 0, 0, 1, 1, 0, 0, true)
-	app.SetFocus(actionSpace)
-}
-
 // ==============================
 // BACKGROUND MODE
 // ==============================
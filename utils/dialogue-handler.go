@@ -0,0 +1,178 @@
+package utils
+
+import (
+    "fmt"
+    "os"
+    "regexp"
+    "strings"
+    "encoding/json"
+    "path/filepath"
+)
+
+// ==============================
+// DIALOGUE STRUCT
+// ==============================
+
+// DialogueRule answers what you say in Talk. The first rule that matches wins.
+type DialogueRule struct {
+    Keywords   []string `json:"keywords,omitempty"`   // whole words, case-insensitive
+    Pattern    string   `json:"pattern,omitempty"`    // regular expression; "{1}" in responses is its first group
+    When       string   `json:"when,omitempty"`       // same tags as encouragements, e.g. "sad" or "morning weekend"
+    Responses  []string `json:"responses"`            // lines she picks from, with {variables}
+    Happiness  int      `json:"happiness,omitempty"`  // happiness change when the rule answers
+    Expression string   `json:"expression,omitempty"` // file in expressions/ shown while answering
+
+    pattern    *regexp.Regexp
+    conditions Conditions
+}
+
+type DialogueFile struct {
+    Rules    []DialogueRule `json:"rules"`
+    Fallback []string       `json:"fallback"` // when no rule matches
+}
+
+var cachedDialogue *DialogueFile
+
+// ==============================
+// DEFAULT DIALOGUE
+// ==============================
+func DefaultDialogue() *DialogueFile {
+    return &DialogueFile{
+        Rules: []DialogueRule{
+            {Keywords: []string{"hi", "hello", "hey", "yo"}, When: "morning",
+                Responses: []string{"Good morning, {user}!", "Morning! Did you sleep well?"}, Happiness: 2, Expression: "-happy"},
+            {Keywords: []string{"hi", "hello", "hey", "yo"},
+                Responses: []string{"Hi {user}!", "Hey you ♥", "Hello! I was waiting for you."}, Happiness: 2, Expression: "-happy"},
+            {Pattern: `(?i)\bi love you\b`,
+                Responses: []string{"I-I love you too!! ♥", "...say that again? ♥"}, Happiness: 10, Expression: "-happy"},
+            {Pattern: `(?i)\bi('m| am) (sad|tired|stressed|exhausted)\b`,
+                Responses: []string{"You're {2}? Take a break with me for a bit.", "Hey... it's okay to be {2}. I'm here."}, Expression: "sad"},
+            {Pattern: `(?i)\bi('m| am) (happy|great|fine|good)\b`,
+                Responses: []string{"Yay! {2} looks good on you ♥"}, Happiness: 3, Expression: "-happy"},
+            {Pattern: `(?i)how are you`, When: "sad",
+                Responses: []string{"Not great... some attention would help.", "A bit lonely, honestly."}, Expression: "sad"},
+            {Pattern: `(?i)how are you`,
+                Responses: []string{"I'm at {happiness}! Thanks for asking.", "Better now that you're talking to me."}, Happiness: 1},
+            {Keywords: []string{"time"},
+                Responses: []string{"It's {time}. Don't forget to rest!"}},
+            {Pattern: `(?i)\b(stupid|dumb|ugly|hate you)\b`,
+                Responses: []string{"...that hurt.", "Why would you say that?"}, Happiness: -10, Expression: "sad"},
+            {Keywords: []string{"bye", "goodbye", "goodnight", "night"},
+                Responses: []string{"Bye bye! Come back soon ♥", "Sweet dreams, {user}."}, Happiness: 1},
+            {Keywords: []string{"thanks", "thank", "ty"},
+                Responses: []string{"Anytime ♥", "That's what I'm here for!"}, Happiness: 2, Expression: "-happy"},
+        },
+        Fallback: []string{"Hmm? Tell me more.", "I see...", "Really? Go on!", "I'm listening, {user}."},
+    }
+}
+
+// ==============================
+// FILE CREATION
+// ==============================
+func CreateDialogueFile() error {
+    configDir := ConfigDir()
+    if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+        return fmt.Errorf("failed to create config directory: %w", err)
+    }
+
+    dialoguePath := filepath.Join(configDir, "dialogue.json")
+
+    if _, err := os.Stat(dialoguePath); err == nil {
+        return nil
+    }
+
+    file, err := os.Create(dialoguePath)
+    if err != nil {
+        return fmt.Errorf("failed to create dialogue file: %w", err)
+    }
+    defer file.Close()
+
+    encoder := json.NewEncoder(file)
+    encoder.SetIndent("", "  ")
+    encoder.SetEscapeHTML(false)
+    if err := encoder.Encode(DefaultDialogue()); err != nil {
+        return fmt.Errorf("failed to write default dialogue: %w", err)
+    }
+
+    return nil
+}
+
+// ==============================
+// LOAD DIALOGUE
+// ==============================
+
+// LoadDialogue loads dialogue.json; a rule with a broken pattern or unknown tag is an error
+// so the user knows which rule to fix
+func LoadDialogue() (*DialogueFile, error) {
+    if cachedDialogue != nil {
+        return cachedDialogue, nil
+    }
+
+    configDir := ConfigDir()
+    dialoguePath := filepath.Join(configDir, "dialogue.json")
+
+    if _, err := os.Stat(dialoguePath); os.IsNotExist(err) {
+        if err := CreateDialogueFile(); err != nil {
+            return nil, err
+        }
+    }
+
+    file, err := os.Open(dialoguePath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open dialogue file: %w", err)
+    }
+    defer file.Close()
+
+    var df DialogueFile
+    if err := json.NewDecoder(file).Decode(&df); err != nil {
+        // fallback to default if JSON broken
+        df = *DefaultDialogue()
+    }
+    if len(df.Fallback) == 0 {
+        df.Fallback = DefaultDialogue().Fallback
+    }
+
+    for i := range df.Rules {
+        r := &df.Rules[i]
+        if r.Pattern != "" {
+            re, err := regexp.Compile(r.Pattern)
+            if err != nil {
+                return nil, fmt.Errorf("dialogue.json: rule %d: invalid pattern: %w", i+1, err)
+            }
+            r.pattern = re
+        }
+        conditions, unknown := ParseConditions(strings.Fields(r.When))
+        if len(unknown) > 0 {
+            return nil, fmt.Errorf("dialogue.json: rule %d: unknown tag %q", i+1, unknown[0])
+        }
+        r.conditions = conditions
+    }
+
+    cachedDialogue = &df
+    return cachedDialogue, nil
+}
+
+// Match reports whether the rule answers `text` in the context, with the pattern groups
+func (r *DialogueRule) Match(text string, ctx TemplateContext) ([]string, bool) {
+    if len(r.Responses) == 0 || !r.conditions.Match(ctx) {
+        return nil, false
+    }
+    if r.pattern != nil {
+        if groups := r.pattern.FindStringSubmatch(text); groups != nil {
+            return groups, true
+        }
+    }
+    if len(r.Keywords) > 0 {
+        words := strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+            return !(c == '\'' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c > 127)
+        })
+        for _, w := range words {
+            for _, k := range r.Keywords {
+                if w == strings.ToLower(k) {
+                    return []string{w}, true
+                }
+            }
+        }
+    }
+    return nil, false
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// COINS
+// ==============================
+
+// Coins returns the current amount of coins
+func Coins() int {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return currentSave().Coins
+}
+
+// AddCoins gives coins (from time, mini-games, focus sessions...)
+func AddCoins(n int) {
+	saveMutex.Lock()
+	currentSave().Coins += n
+	saveMutex.Unlock()
+
+	updateCoins()
+}
+
+// SpendCoins takes coins if there are enough of them
+func SpendCoins(n int) bool {
+	saveMutex.Lock()
+	s := currentSave()
+	if s.Coins < n {
+		saveMutex.Unlock()
+		return false
+	}
+	s.Coins -= n
+	saveMutex.Unlock()
+
+	updateCoins()
+	return true
+}
+
+// updateCoins shows the coins in the happiness bar title
+func updateCoins() {
+	SetStatusItem("coins", fmt.Sprintf("%d¢", Coins()))
+}
+
+// StartEarning gives `perMinute` coins for every minute the app stays open
+func StartEarning(perMinute int) {
+	updateCoins()
+	if perMinute <= 0 {
+		return
+	}
+
+	var last time.Time
+	OnTick(func(now time.Time) {
+		if last.IsZero() {
+			last = now
+			return
+		}
+		if now.Sub(last) >= time.Minute {
+			last = now
+			AddCoins(perMinute)
+		}
+	})
+}
+
+// ==============================
+// INVENTORY
+// ==============================
+
+// InventoryCount returns how many of a gift are owned
+func InventoryCount(name string) int {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return currentSave().Inventory[name]
+}
+
+// AddToInventory stores `n` more of a gift
+func AddToInventory(name string, n int) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	currentSave().Inventory[name] += n
+}
+
+// TakeFromInventory removes one gift if it is owned
+func TakeFromInventory(name string) bool {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	inv := currentSave().Inventory
+	if inv[name] <= 0 {
+		return false
+	}
+	inv[name]--
+	if inv[name] == 0 {
+		delete(inv, name)
+	}
+	return true
+}
+
+// OwnedGifts returns the gifts of gifts.json that are in the inventory
+func OwnedGifts() []Gift {
+	var owned []Gift
+	for _, g := range AvailableGifts() {
+		if InventoryCount(g.Name) > 0 {
+			owned = append(owned, g)
+		}
+	}
+	return owned
+}
+
+// ==============================
+// SHOP
+// ==============================
+
+// ShopMenu lists the gifts with their prices; buying keeps the menu open
+func ShopMenu(
+	app *tview.Application,
+	grid *tview.Grid,
+	actionSpace *tview.List,
+	chatBox *tview.TextView,
+) {
+	gifts := AvailableGifts()
+	if len(gifts) == 0 {
+		showChatMessage(chatBox, "The shop is empty!")
+		return
+	}
+
+	list := tview.NewList()
+	ApplyListPalette(cachedPalette, list)
+
+	shopLine := func(g Gift) (string, string) {
+		return fmt.Sprintf("- %s  %d¢", g.Name, g.Cost()),
+			fmt.Sprintf("  %+d happiness, owned: %d", g.Effect(), InventoryCount(g.Name))
+	}
+
+	for i, g := range gifts {
+		index, gift := i, g
+		main, secondary := shopLine(gift)
+
+		list.AddItem(main, secondary, 0, func() {
+			if !SpendCoins(gift.Cost()) {
+				showChatMessage(chatBox, fmt.Sprintf("Not enough coins for the %s (%d¢, you have %d¢).",
+					gift.Name, gift.Cost(), Coins()))
+				return
+			}
+			AddToInventory(gift.Name, 1)
+			showChatMessage(chatBox, fmt.Sprintf("Bought a %s! You have %d¢ left.", gift.Name, Coins()))
+
+			main, secondary := shopLine(gift)
+			list.SetItemText(index, main, secondary)
+		})
+	}
+
+	list.SetBorder(true).SetTitle("| Shop |").SetTitleAlign(tview.AlignCenter)
+	list.SetDoneFunc(func() {
+		closeGiftMenu(app, grid, list, actionSpace)
+	})
+
+	// No other menu may take the grid until the shop is closed
+	LockGridChanges = true
+	grid.RemoveItem(actionSpace)
+	grid.AddItem(list, 0, 0, 1, 1, 0, 0, true)
+	app.SetFocus(list)
+}
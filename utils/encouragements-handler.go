@@ -5,25 +5,41 @@ import (
 	"io"
 	"fmt"
 	"bufio"
+	"strconv"
+	"strings"
 	"path/filepath"
 )
 
+// ==============================
+// ENCOURAGEMENT STRUCT
+// ==============================
+
+// Encouragement is one line of the file: "[tags] text with {variables}"
+type Encouragement struct {
+	Text       string
+	Conditions Conditions
+	Weight     int // "weight=3" makes a line three times as likely
+}
+
+// How many shown lines are remembered in save.json
+const encouragementHistorySize = 50
+
 // ==============================
 // CACHED ENCOURAGEMENTS
 // ==============================
-var cachedEncouragements []string
+var cachedEncouragements []Encouragement
 
 // ==============================
 // LOAD ENCOURAGEMENTS
 // ==============================
 // LoadEncouragements loads the encouragements list from the config directory
 // If missing, it recreates it from the embedded asset
-func LoadEncouragements(_ string) ([]string, error) {
+func LoadEncouragements(_ string) ([]Encouragement, error) {
 	if cachedEncouragements != nil {
 		return cachedEncouragements, nil
 	}
 
-	configDir := filepath.Join(os.Getenv("HOME"), ".config", "cliwaifutamagotchi")
+	configDir := ConfigDir()
 	if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
 		return nil, fmt.Errorf("failed creating config dir: %w", err)
 	}
@@ -37,13 +53,78 @@ func LoadEncouragements(_ string) ([]string, error) {
 		}
 	}
 
+	// Best effort: without them the older lines still work, and it is tried again next launch
+	_ = addTaggedDefaults(configPath)
+
 	lines, err := readEncFile(configPath)
 	if err != nil {
 		return nil, err
 	}
 
-	cachedEncouragements = lines
-	return lines, nil
+	encouragements := make([]Encouragement, 0, len(lines))
+	for _, line := range lines {
+		encouragements = append(encouragements, parseEncouragement(line))
+	}
+
+	cachedEncouragements = encouragements
+	return encouragements, nil
+}
+
+// ==============================
+// PARSE TAGS
+// ==============================
+
+// parseEncouragement splits the optional leading "[tags]" from the text.
+// If a tag is unknown, the brackets are kept as part of the text.
+func parseEncouragement(line string) Encouragement {
+	end := strings.Index(line, "]")
+	if !strings.HasPrefix(line, "[") || end < 0 {
+		return Encouragement{Text: line, Weight: 1}
+	}
+
+	conditions, rest := ParseConditions(strings.Fields(line[1:end]))
+	weight := 1
+	for _, tag := range rest {
+		value, ok := strings.CutPrefix(strings.ToLower(tag), "weight=")
+		n, err := strconv.Atoi(value)
+		if !ok || err != nil || n < 0 {
+			return Encouragement{Text: line, Weight: 1}
+		}
+		weight = n
+	}
+	return Encouragement{
+		Text:       strings.TrimSpace(line[end+1:]),
+		Conditions: conditions,
+		Weight:     weight,
+	}
+}
+
+// ==============================
+// HISTORY
+// ==============================
+
+// recentEncouragements returns the last `n` lines shown, newest last
+func recentEncouragements(n int) []string {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	history := currentSave().EncouragementHistory
+	if n > len(history) {
+		n = len(history)
+	}
+	return history[len(history)-n:]
+}
+
+// rememberEncouragement appends a shown line to the persisted history
+func rememberEncouragement(text string) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	s.EncouragementHistory = append(s.EncouragementHistory, text)
+	if extra := len(s.EncouragementHistory) - encouragementHistorySize; extra > 0 {
+		s.EncouragementHistory = s.EncouragementHistory[extra:]
+	}
 }
 
 // ==============================
@@ -69,6 +150,62 @@ func createEncFile(dst string) error {
 	return nil
 }
 
+// ==============================
+// NEW DEFAULT LINES
+// ==============================
+
+// addTaggedDefaults appends the default lines with [tags] that a file written before them lacks.
+// It runs once per profile (remembered in save.json), so lines removed afterwards stay removed.
+func addTaggedDefaults(path string) error {
+	saveMutex.Lock()
+	done := currentSave().TaggedEncouragements
+	saveMutex.Unlock()
+	if done {
+		return nil
+	}
+
+	lines, err := readEncFile(path)
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		have[line] = true
+	}
+
+	defaults, err := ASSETSFS.ReadFile("assets/words-of-encouragement.txt")
+	if err != nil {
+		return fmt.Errorf("missing embedded default encouragements: %w", err)
+	}
+	var missing []string
+	for _, line := range strings.Split(string(defaults), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "[") && !have[line] {
+			missing = append(missing, line)
+		}
+	}
+
+	if len(missing) > 0 {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		// The file may not end with a newline; empty lines are skipped when reading
+		_, err = f.WriteString("\n" + strings.Join(missing, "\n") + "\n")
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add the new encouragements: %w", err)
+		}
+	}
+
+	saveMutex.Lock()
+	currentSave().TaggedEncouragements = true
+	saveMutex.Unlock()
+	return nil
+}
+
 // ==============================
 // READ LINES FROM FILE
 // ==============================
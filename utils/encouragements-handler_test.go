@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"os"
+	"time"
+	"reflect"
+	"strings"
+	"testing"
+	"path/filepath"
+)
+
+func TestParseEncouragement(t *testing.T) {
+	tests := []struct {
+		line string
+		want Encouragement
+	}{
+		{"You can do it!", Encouragement{Text: "You can do it!", Weight: 1}},
+		{"[morning] Good morning, {user}!", Encouragement{
+			Text:       "Good morning, {user}!",
+			Conditions: Conditions{Times: []string{"morning"}},
+			Weight:     1,
+		}},
+		{"[sat sun weight=3]  Weekend!", Encouragement{
+			Text:       "Weekend!",
+			Conditions: Conditions{Days: []time.Weekday{time.Saturday, time.Sunday}},
+			Weight:     3,
+		}},
+		{"[weight=0] Hidden", Encouragement{Text: "Hidden", Weight: 0}},
+		{"[] Empty tags", Encouragement{Text: "Empty tags", Weight: 1}},
+		// Unknown or invalid tags keep the line as it is
+		{"[brb] back soon", Encouragement{Text: "[brb] back soon", Weight: 1}},
+		{"[weight=-1] no", Encouragement{Text: "[weight=-1] no", Weight: 1}},
+		{"[weight=lots] no", Encouragement{Text: "[weight=lots] no", Weight: 1}},
+		{"[morning no bracket", Encouragement{Text: "[morning no bracket", Weight: 1}},
+		{"Hi :] there", Encouragement{Text: "Hi :] there", Weight: 1}},
+	}
+	for _, tt := range tests {
+		if got := parseEncouragement(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseEncouragement(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestAddTaggedDefaults(t *testing.T) {
+	previous := cachedSave
+	cachedSave = &SaveState{}
+	t.Cleanup(func() { cachedSave = previous })
+
+	path := filepath.Join(t.TempDir(), "words-of-encouragement.txt")
+	mine := "[morning] Good morning, {user}! Did you sleep well?\nMy own line"
+	if err := os.WriteFile(path, []byte(mine), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := addTaggedDefaults(path); err != nil {
+		t.Fatalf("addTaggedDefaults: %v", err)
+	}
+	lines, _ := readEncFile(path)
+	if len(lines) < 3 || lines[1] != "My own line" || !strings.HasPrefix(lines[2], "[") {
+		t.Fatalf("unexpected file %q", lines)
+	}
+	seen := map[string]bool{}
+	for _, line := range lines {
+		if seen[line] {
+			t.Errorf("%q was added twice", line)
+		}
+		seen[line] = true
+	}
+	if !cachedSave.TaggedEncouragements {
+		t.Error("the update wasn't remembered")
+	}
+
+	// Once done, removed lines stay removed
+	if err := os.WriteFile(path, []byte(mine), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := addTaggedDefaults(path); err != nil {
+		t.Fatalf("addTaggedDefaults: %v", err)
+	}
+	if lines, _ := readEncFile(path); len(lines) != 2 {
+		t.Errorf("lines were added again: %q", lines)
+	}
+}
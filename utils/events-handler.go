@@ -0,0 +1,163 @@
+package utils
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "encoding/json"
+    "path/filepath"
+)
+
+// ==============================
+// EVENTS STRUCT
+// ==============================
+type EventEffects struct {
+    Happiness int    `json:"happiness,omitempty"` // can be negative
+    Coins     int    `json:"coins,omitempty"`
+    XP        int    `json:"xp,omitempty"`
+    Gift      string `json:"gift,omitempty"` // gift added to the inventory
+}
+
+type RandomEvent struct {
+    ID         string       `json:"id"`
+    Chance     float64      `json:"chance"`               // probability per minute, 0-1
+    Cooldown   int          `json:"cooldown,omitempty"`   // minutes before it can happen again
+    When       string       `json:"when,omitempty"`       // same tags as encouragements
+    Lines      []string     `json:"lines"`                // one is said, with {variables} and {gift}
+    Expression string       `json:"expression,omitempty"` // file in expressions/ shown with the line
+    Suggest    string       `json:"suggest,omitempty"`    // action whose key is shown, e.g. "play"
+    Effects    EventEffects `json:"effects"`
+
+    conditions Conditions
+}
+
+type EventsFile struct {
+    Events []RandomEvent `json:"events"`
+    MinGap int           `json:"minGap"` // minutes between two events
+}
+
+var cachedEvents *EventsFile
+
+// ==============================
+// DEFAULT EVENTS
+// ==============================
+func DefaultEvents() *EventsFile {
+    return &EventsFile{
+        Events: []RandomEvent{
+            {
+                ID: "found-coin", Chance: 0.02, Cooldown: 60,
+                Lines:      []string{"Look, {user}! I found a coin on the floor~", "Ooh, something shiny! A coin!"},
+                Expression: "-happy",
+                Effects:    EventEffects{Coins: 5},
+            },
+            {
+                ID: "bored", Chance: 0.04, Cooldown: 30, When: "mood<=700",
+                Lines:      []string{"I'm bored... wanna play something with me?", "Heeey, {user}. Play with me a little?"},
+                Expression: "bored",
+                Suggest:    "play",
+            },
+            {
+                ID: "wants-gift", Chance: 0.02, Cooldown: 90,
+                Lines:      []string{"I keep thinking about a {gift}... just saying!", "You know what would be nice? A {gift}."},
+                Expression: "talking",
+                Suggest:    "gift",
+            },
+            {
+                ID: "hums", Chance: 0.03, Cooldown: 20, When: "happy",
+                Lines:      []string{"♪ La la la~ ♪", "*hums a little song* ♪", "♪ Doo doo~ ♪ ...did you hear that?"},
+                Expression: "talking",
+                Effects:    EventEffects{Happiness: 5},
+            },
+            {
+                ID: "window-cat", Chance: 0.01, Cooldown: 120, When: "morning afternoon",
+                Lines:      []string{"A cat just walked past the window! So fluffy...", "There's a bird on the window sill! Hi, little one~"},
+                Expression: "-happy",
+                Effects:    EventEffects{Happiness: 10, XP: 2},
+            },
+            {
+                ID: "yawn", Chance: 0.05, Cooldown: 30, When: "night",
+                Lines:      []string{"*yawns* ...sorry. Are you still going?", "Mmh... I'm getting sleepy."},
+                Expression: "sleepy",
+            },
+        },
+        MinGap: 10,
+    }
+}
+
+// ==============================
+// FILE CREATION
+// ==============================
+func CreateEventsFile() error {
+    configDir := ConfigDir()
+    if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+        return fmt.Errorf("failed to create config directory: %w", err)
+    }
+
+    eventsPath := filepath.Join(configDir, "events.json")
+
+    if _, err := os.Stat(eventsPath); err == nil {
+        return nil
+    }
+
+    file, err := os.Create(eventsPath)
+    if err != nil {
+        return fmt.Errorf("failed to create events file: %w", err)
+    }
+    defer file.Close()
+
+    encoder := json.NewEncoder(file)
+    encoder.SetIndent("", "  ")
+    if err := encoder.Encode(DefaultEvents()); err != nil {
+        return fmt.Errorf("failed to write default events: %w", err)
+    }
+
+    return nil
+}
+
+// ==============================
+// LOAD EVENTS
+// ==============================
+func LoadEvents() (*EventsFile, error) {
+    if cachedEvents != nil {
+        return cachedEvents, nil
+    }
+
+    configDir := ConfigDir()
+    eventsPath := filepath.Join(configDir, "events.json")
+
+    if _, err := os.Stat(eventsPath); os.IsNotExist(err) {
+        if err := CreateEventsFile(); err != nil {
+            return nil, err
+        }
+    }
+
+    file, err := os.Open(eventsPath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open events file: %w", err)
+    }
+    defer file.Close()
+
+    var ef EventsFile
+    if err := json.NewDecoder(file).Decode(&ef); err != nil {
+        // fallback to default if JSON broken
+        ef = *DefaultEvents()
+    }
+    if ef.MinGap < 0 {
+        ef.MinGap = 0
+    }
+
+    // Events without lines, with an impossible chance or with unknown tags are skipped
+    valid := ef.Events[:0]
+    for _, e := range ef.Events {
+        conditions, unknown := ParseConditions(strings.Fields(e.When))
+        if len(e.Lines) == 0 || e.Chance <= 0 || e.Chance > 1 || len(unknown) > 0 {
+            continue
+        }
+        e.conditions = conditions
+        valid = append(valid, e)
+    }
+    ef.Events = valid
+
+    cachedEvents = &ef
+    return cachedEvents, nil
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	"strings"
+	"math/rand"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// RANDOM EVENTS
+// ==============================
+
+// EventEntry is one event that happened, kept in save.json
+type EventEntry struct {
+	Time time.Time `json:"time"`
+	ID   string    `json:"id"`
+	Text string    `json:"text"`
+}
+
+// How many events are remembered in save.json
+const eventLogSize = 100
+
+// logEvent appends an event to the persisted log
+func logEvent(id, text string) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	s.EventLog = append(s.EventLog, EventEntry{Time: time.Now(), ID: id, Text: text})
+	if extra := len(s.EventLog) - eventLogSize; extra > 0 {
+		s.EventLog = s.EventLog[extra:]
+	}
+}
+
+// EventLog returns a copy of the logged events, oldest first
+func EventLog() []EventEntry {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return append([]EventEntry(nil), currentSave().EventLog...)
+}
+
+// applyEffects applies what an event does and describes the rewards, e.g. "+5¢"
+func applyEffects(e EventEffects) []string {
+	var rewards []string
+	switch {
+	case e.Happiness > 0:
+		IncreaseHappiness(e.Happiness)
+	case e.Happiness < 0:
+		DecreaseHappiness(-e.Happiness)
+	}
+	if e.Coins > 0 {
+		AddCoins(e.Coins)
+		rewards = append(rewards, fmt.Sprintf("+%d¢", e.Coins))
+	}
+	if e.Gift != "" {
+		AddToInventory(e.Gift, 1)
+		rewards = append(rewards, "+1 "+e.Gift)
+	}
+	AddXP(e.XP)
+	return rewards
+}
+
+// StartEvents rolls the events of events.json once a minute on the tick loop.
+// At most one happens at a time, with "minGap" minutes between two of them.
+func StartEvents(
+	waifuArt, chatBox *tview.TextView,
+	head *string,
+	waifuName string,
+	currentBody *string,
+	keys KeyBindings,
+) error {
+	ef, err := LoadEvents()
+	if err != nil {
+		return err
+	}
+	if len(ef.Events) == 0 {
+		return nil
+	}
+
+	lastRoll, lastEvent := time.Now(), time.Time{}
+	lastFired := map[string]time.Time{}
+
+	OnTick(func(now time.Time) {
+		if now.Sub(lastRoll) < time.Minute {
+			return
+		}
+		lastRoll = now
+		if now.Sub(lastEvent) < time.Duration(ef.MinGap)*time.Minute {
+			return
+		}
+
+		ctx := CurrentContext()
+		for _, e := range rand.Perm(len(ef.Events)) {
+			event := ef.Events[e]
+			if last, ok := lastFired[event.ID]; ok && now.Sub(last) < time.Duration(event.Cooldown)*time.Minute {
+				continue
+			}
+			if !event.conditions.Match(ctx) || rand.Float64() >= event.Chance {
+				continue
+			}
+
+			lastEvent, lastFired[event.ID] = now, now
+			line := event.Lines[rand.Intn(len(event.Lines))]
+			if gifts := AvailableGifts(); len(gifts) > 0 {
+				line = strings.ReplaceAll(line, "{gift}", gifts[rand.Intn(len(gifts))].Name)
+			}
+			line = RenderTemplate(line, ctx)
+			logEvent(event.ID, line)
+
+			text := waifuName + ": " + line
+			if rewards := applyEffects(event.Effects); len(rewards) > 0 {
+				text += " (" + strings.Join(rewards, ", ") + ")"
+			}
+			if action := FindAction(event.Suggest); action != nil {
+				text += " [" + keys.For(action).Label() + "]"
+			}
+
+			face := ""
+			if event.Expression != "" {
+				face = LoadOptionalASCII(BasePath+"/expressions/"+event.Expression, "")
+			}
+			UIEventsChan <- func() {
+				SetChat(chatBox, text)
+				if face == "" {
+					return
+				}
+				waifuArt.SetText(face + "\n" + *currentBody)
+				time.AfterFunc(3*time.Second, func() {
+					UIEventsChan <- func() {
+						waifuArt.SetText(*head + "\n" + *currentBody)
+					}
+				})
+			}
+			return // one at a time
+		}
+	})
+	return nil
+}
+
+// ==============================
+// EVENT LOG VIEW
+// ==============================
+
+// ShowEventLog opens the list of past events
+func ShowEventLog(app *tview.Application, pages *tview.Pages, toggle KeyBinding) {
+	if OverlayOpen(pages) {
+		return
+	}
+
+	var b strings.Builder
+	var last time.Time
+	entries := EventLog()
+	for _, e := range entries {
+		if !sameDay(last, e.Time) {
+			b.WriteString(dayHeader(e.Time))
+		}
+		fmt.Fprintf(&b, "[::d]%s[::-] %s [::d](%s)[::-]\n", e.Time.Format("15:04"), tview.Escape(e.Text), e.ID)
+		last = e.Time
+	}
+	if len(entries) == 0 {
+		b.WriteString("Nothing happened yet.\n")
+	}
+
+	view := showTextModal(app, pages, "events", "| Event Log (Esc) |", b.String(), toggle, 80, 24)
+	view.ScrollToEnd()
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// FOCUS TIMER
+// ==============================
+
+// FocusDay is what was done in one day, stored in save.json by date ("2006-01-02")
+type FocusDay struct {
+	Sessions int `json:"sessions"` // completed work sessions
+	Minutes  int `json:"minutes"`  // minutes of completed work sessions
+}
+
+// stop channel of the running timer, nil when no timer runs (UI goroutine only)
+var focusStop chan struct{}
+
+// FocusRunning reports whether a focus timer is running
+func FocusRunning() bool {
+	return focusStop != nil
+}
+
+// FocusToday returns today's focus stats
+func FocusToday() FocusDay {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return currentSave().FocusStats[time.Now().Format("2006-01-02")]
+}
+
+// recordFocus adds a completed work session to today's stats
+func recordFocus(minutes int) FocusDay {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	if s.FocusStats == nil {
+		s.FocusStats = map[string]FocusDay{}
+	}
+	day := time.Now().Format("2006-01-02")
+	stats := s.FocusStats[day]
+	stats.Sessions++
+	stats.Minutes += minutes
+	s.FocusStats[day] = stats
+	return stats
+}
+
+// ToggleFocus starts work/break cycles ("focus" in settings.json), or stops the running ones.
+// The countdown is shown next to the happiness bar; she cheers when a work session ends.
+func ToggleFocus(
+	waifuArt, chatBox *tview.TextView,
+	head *string,
+	happyHead, waifuName string,
+	currentBody *string,
+) {
+	if focusStop != nil {
+		close(focusStop)
+		focusStop = nil
+		today := FocusToday()
+		SetChat(chatBox, fmt.Sprintf("%s: Focus stopped. Today: %d sessions, %d minutes.",
+			waifuName, today.Sessions, today.Minutes))
+		return
+	}
+
+	cfg := DefaultSettings().Focus
+	if s, err := LoadSettings(); err == nil {
+		cfg = s.Focus
+	}
+	if cfg.Work <= 0 {
+		cfg.Work = DefaultSettings().Focus.Work
+	}
+
+	stop := make(chan struct{})
+	focusStop = stop
+	SetChat(chatBox, fmt.Sprintf("%s: %d minutes of focus, starting now. You can do it!", waifuName, cfg.Work))
+
+	// say runs on the UI goroutine unless the timer was stopped meanwhile
+	say := func(line string, face string, happiness, coins int) {
+		UIEventsChan <- func() {
+			if focusStop != stop {
+				return
+			}
+			SetChat(chatBox, waifuName+": "+line)
+			if happiness > 0 {
+				IncreaseHappiness(happiness)
+			}
+			if coins > 0 {
+				AddCoins(coins)
+			}
+			if face == "" {
+				return
+			}
+			waifuArt.SetText(face + "\n" + *currentBody)
+			time.AfterFunc(2*time.Second, func() {
+				UIEventsChan <- func() {
+					waifuArt.SetText(*head + "\n" + *currentBody)
+				}
+			})
+		}
+	}
+
+	go func() {
+		for cycle := 1; ; cycle++ {
+			if !focusCountdown(stop, "work", time.Duration(cfg.Work)*time.Minute) {
+				return
+			}
+
+			stats := recordFocus(cfg.Work)
+			TrackStat("focus", 1)
+			long := cfg.Cycles > 0 && cycle%cfg.Cycles == 0
+			rest := cfg.ShortBreak
+			if long {
+				rest = cfg.LongBreak
+			}
+			line := fmt.Sprintf("Session done! That's %d today ♥ Now take a %d minute break, stretch and drink some water.",
+				stats.Sessions, rest)
+			if long {
+				line = fmt.Sprintf("%d sessions in a row! You earned a long %d minute break ♥", cfg.Cycles, rest)
+			}
+			if cfg.Coins > 0 {
+				line += fmt.Sprintf(" (+%d¢)", cfg.Coins)
+			}
+			say(line, happyHead, cfg.Happiness, cfg.Coins)
+
+			if rest > 0 {
+				if !focusCountdown(stop, "break", time.Duration(rest)*time.Minute) {
+					return
+				}
+				say("Break's over! Ready for another round?", "", 0, 0)
+			}
+		}
+	}()
+}
+
+// focusCountdown shows the time left every second; returns false if stopped
+func focusCountdown(stop chan struct{}, label string, d time.Duration) bool {
+	end := time.Now().Add(d)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		left := time.Until(end).Round(time.Second)
+		if left <= 0 {
+			return true
+		}
+		SetStatusItem("focus", fmt.Sprintf("%s %02d:%02d", label, int(left.Minutes()), int(left.Seconds())%60))
+
+		select {
+		case <-stop:
+			SetStatusItem("focus", "")
+			return false
+		case <-ticker.C:
+		}
+	}
+}
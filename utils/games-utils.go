@@ -0,0 +1,359 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	"strconv"
+	"strings"
+	"math/rand"
+
+	"github.com/rivo/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ==============================
+// MINI-GAMES
+// ==============================
+
+// miniGame is one entry of the Play menu. `start` builds the game in the Action Space
+// and calls `finish` with the score once it is over, plus a note said before her reaction.
+type miniGame struct {
+	ID          string
+	Name        string
+	Description string
+	Happiness   func(score int) int // happiness earned for a score
+	start       func(s *gameSession, finish func(score int, note string)) (tview.Primitive, tview.Primitive)
+}
+
+// gameSession is what a running game may touch; every game gets its own,
+// so timers of a game that was left can't act on the next one
+type gameSession struct {
+	app       *tview.Application
+	chatBox   *tview.TextView
+	waifuName string
+	title     func(string) // sets the title of the game box
+	over      bool         // set once the player left the game
+}
+
+// say shows a line from her in the chat box
+func (s *gameSession) say(line string) {
+	SetChat(s.chatBox, s.waifuName+": "+line)
+}
+
+// whisper shows a line without keeping it in the chat history, for what must not be read back
+func (s *gameSession) whisper(line string) {
+	ShowChat(s.chatBox, s.waifuName+": "+line)
+}
+
+var miniGames = []miniGame{
+	{
+		ID:          "rps",
+		Name:        "Rock Paper Scissors",
+		Description: "First to 3 wins.",
+		Happiness:   func(score int) int { return score * 3 },
+		start:       startRockPaperScissors,
+	},
+	{
+		ID:          "guess",
+		Name:        "Guess the Number",
+		Description: "1-100 in 7 tries.",
+		Happiness:   func(score int) int { return score * 2 },
+		start:       startGuessNumber,
+	},
+	{
+		ID:          "memory",
+		Name:        "Memory",
+		Description: "Repeat her sequence.",
+		Happiness:   func(score int) int { return score * 2 },
+		start:       startMemory,
+	},
+}
+
+// HighScore returns the best score of a game
+func HighScore(id string) int {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return currentSave().HighScores[id]
+}
+
+// recordScore stores the score and reports whether it beat the high score
+func recordScore(id string, score int) bool {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	if s.HighScores == nil {
+		s.HighScores = map[string]int{}
+	}
+	if score <= s.HighScores[id] {
+		return false
+	}
+	s.HighScores[id] = score
+	return true
+}
+
+// PlayMenu swaps the Action Space for the list of games, like GiftMenu
+func PlayMenu(
+	app *tview.Application,
+	grid *tview.Grid,
+	actionSpace *tview.List,
+	waifuArt, chatBox *tview.TextView,
+	head, happyHead, waifuName string,
+	currentBody *string,
+) {
+	list := tview.NewList()
+	ApplyListPalette(cachedPalette, list)
+
+	for _, g := range miniGames {
+		game := g
+		list.AddItem("- "+game.Name, fmt.Sprintf("  %s Best: %d", game.Description, HighScore(game.ID)), 0, func() {
+			// The game takes the place of the menu; Esc in a game goes back to it
+			box := tview.NewFlex().SetDirection(tview.FlexRow)
+			box.SetBorder(true).SetTitleAlign(tview.AlignCenter)
+			box.SetBackgroundColor(tcell.GetColor(cachedPaletteOrDefault().Background))
+			box.SetBorderColor(tcell.GetColor(cachedPaletteOrDefault().Border))
+			box.SetTitleColor(tcell.GetColor(cachedPaletteOrDefault().Title))
+			session := &gameSession{app: app, chatBox: chatBox, waifuName: waifuName}
+			session.title = func(t string) { box.SetTitle("| " + t + " |") }
+			session.title(game.Name)
+
+			back := func() {
+				session.over = true
+				grid.RemoveItem(box)
+				grid.AddItem(list, 0, 0, 1, 1, 0, 0, true)
+				app.SetFocus(list)
+			}
+			finish := func(score int, note string) {
+				rewardGame(game, score, note, waifuArt, chatBox, head, happyHead, waifuName, currentBody)
+				list.SetItemText(list.GetCurrentItem(), "- "+game.Name,
+					fmt.Sprintf("  %s Best: %d", game.Description, HighScore(game.ID)))
+				back()
+			}
+
+			content, focus := game.start(session, finish)
+			box.AddItem(content, 0, 1, true)
+			box.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					back()
+					return nil
+				}
+				return event
+			})
+
+			grid.RemoveItem(list)
+			grid.AddItem(box, 0, 0, 1, 1, 0, 0, true)
+			app.SetFocus(focus)
+		})
+	}
+
+	list.SetBorder(true).SetTitle("| Play |").SetTitleAlign(tview.AlignCenter)
+	list.SetDoneFunc(func() {
+		closeGiftMenu(app, grid, list, actionSpace)
+	})
+
+	// No other menu may take the grid until the Play menu is closed
+	LockGridChanges = true
+	grid.RemoveItem(actionSpace)
+	grid.AddItem(list, 0, 0, 1, 1, 0, 0, true)
+	app.SetFocus(list)
+}
+
+// Coins earned for each point of a game score
+const gameCoinsPerPoint = 1
+
+// rewardGame turns a score into happiness, coins and a reaction (called from the UI goroutine)
+func rewardGame(
+	game miniGame, score int, note string,
+	waifuArt, chatBox *tview.TextView,
+	head, happyHead, waifuName string,
+	currentBody *string,
+) {
+	amount := game.Happiness(score)
+	coins := max(score, 0) * gameCoinsPerPoint
+	record := recordScore(game.ID, score)
+	TrackStat("games", 1)
+
+	var line string
+	face := happyHead
+	switch {
+	case record:
+		line = fmt.Sprintf("New record at %s: %d! You're amazing ♥", game.Name, score)
+	case amount > 0:
+		line = fmt.Sprintf("That was fun! Score: %d. Again?", score)
+	default:
+		line = "Aww... we'll do better next time!"
+		face = sad
+	}
+	if note != "" {
+		line = note + " " + line
+	}
+	if coins > 0 {
+		AddCoins(coins)
+		line += fmt.Sprintf(" (+%d¢)", coins)
+	}
+
+	SetChat(chatBox, waifuName+": "+line)
+	waifuArt.SetText(face + "\n" + *currentBody)
+	if amount > 0 {
+		IncreaseHappiness(amount)
+	}
+
+	time.AfterFunc(2*time.Second, func() {
+		if UIEventsChan != nil {
+			UIEventsChan <- func() {
+				waifuArt.SetText(head + "\n" + *currentBody)
+			}
+		}
+	})
+}
+
+// gameInput returns an input field in the colors of the palette
+func gameInput(label string) *tview.InputField {
+	p := cachedPaletteOrDefault()
+	return tview.NewInputField().
+		SetLabel(label).
+		SetFieldBackgroundColor(tcell.GetColor(p.Background)).
+		SetFieldTextColor(tcell.GetColor(p.Foreground)).
+		SetLabelColor(tcell.GetColor(p.Accent))
+}
+
+// ==============================
+// ROCK PAPER SCISSORS
+// ==============================
+
+var rpsMoves = []string{"Rock", "Paper", "Scissors"}
+
+// startRockPaperScissors plays until one side has 3 wins; the score is your wins
+func startRockPaperScissors(s *gameSession, finish func(score int, note string)) (tview.Primitive, tview.Primitive) {
+	list := tview.NewList()
+	ApplyListPalette(cachedPaletteOrDefault(), list)
+	list.SetBorder(false)
+
+	you, her := 0, 0
+	s.say("Rock, paper, scissors... first to 3!")
+
+	for i, m := range rpsMoves {
+		move := i
+		list.AddItem(m, "", 0, func() {
+			hers := rand.Intn(3)
+			var line string
+			switch (move - hers + 3) % 3 {
+			case 0:
+				line = rpsMoves[hers] + "! A draw, again!"
+			case 1:
+				you++
+				line = rpsMoves[hers] + "... you got me!"
+			case 2:
+				her++
+				line = rpsMoves[hers] + "! Hehe, mine!"
+			}
+			s.title(fmt.Sprintf("You %d - %d Her", you, her))
+
+			if you < 3 && her < 3 {
+				s.say(line)
+				return
+			}
+			score := you
+			if you == 3 {
+				score += 2 // bonus for winning the match
+			}
+			finish(score, line)
+		})
+	}
+	return list, list
+}
+
+// ==============================
+// GUESS THE NUMBER
+// ==============================
+
+const guessTries = 7
+
+// startGuessNumber: the score is the tries left (+1) when the number is found
+func startGuessNumber(s *gameSession, finish func(score int, note string)) (tview.Primitive, tview.Primitive) {
+	number := rand.Intn(100) + 1
+	tries := 0
+
+	input := gameInput("Guess: ").SetAcceptanceFunc(tview.InputFieldInteger)
+	s.say(fmt.Sprintf("I'm thinking of a number from 1 to 100. You have %d tries!", guessTries))
+	s.title(fmt.Sprintf("Guess the Number (%d tries)", guessTries))
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		n, err := strconv.Atoi(input.GetText())
+		input.SetText("")
+		if err != nil {
+			return
+		}
+		tries++
+		left := guessTries - tries
+		s.title(fmt.Sprintf("Guess the Number (%d tries)", left))
+
+		switch {
+		case n == number:
+			finish(left+1, fmt.Sprintf("%d, yes!", number))
+		case left == 0:
+			finish(0, fmt.Sprintf("It was %d!", number))
+		case n < number:
+			s.say(fmt.Sprintf("Higher than %d!", n))
+		default:
+			s.say(fmt.Sprintf("Lower than %d!", n))
+		}
+	})
+	return input, input
+}
+
+// ==============================
+// MEMORY
+// ==============================
+
+var memorySymbols = []rune("123456789")
+
+// startMemory shows a sequence that grows each round; the score is the rounds recalled
+func startMemory(s *gameSession, finish func(score int, note string)) (tview.Primitive, tview.Primitive) {
+	var sequence []rune
+	round := 0
+
+	input := gameInput("Repeat: ")
+	var next func()
+	next = func() {
+		sequence = append(sequence, memorySymbols[rand.Intn(len(memorySymbols))])
+		shown := string(sequence)
+		input.SetDisabled(true)
+		s.title(fmt.Sprintf("Memory (round %d)", round+1))
+		s.whisper("Remember: " + strings.Join(strings.Split(shown, ""), " "))
+
+		// Hide the sequence after a while, longer for longer ones
+		time.AfterFunc(time.Second+time.Duration(len(sequence))*400*time.Millisecond, func() {
+			if UIEventsChan == nil {
+				return
+			}
+			UIEventsChan <- func() {
+				if s.over {
+					return
+				}
+				s.say("Your turn!")
+				input.SetDisabled(false)
+			}
+		})
+	}
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		answer := strings.ReplaceAll(input.GetText(), " ", "")
+		input.SetText("")
+		if answer != string(sequence) {
+			finish(round, "It was "+string(sequence)+"!")
+			return
+		}
+		round++
+		next()
+	})
+
+	next()
+	return input, input
+}
@@ -3,6 +3,7 @@ package utils
 import (
     "fmt"
     "os"
+    "strings"
     "encoding/json"
     "path/filepath"
 )
@@ -11,12 +12,37 @@ import (
 // GIFTS STRUCT
 // ==============================
 type Gift struct {
-    Name        string `json:"name"`
-    Happiness   int    `json:"happiness"`
+    Name        string   `json:"name"`
+    Happiness   int      `json:"happiness"`
+    Price       int      `json:"price"`                // coins in the Shop; 0 means "same as happiness"
+    Reactions   []string `json:"reactions,omitempty"`  // lines she picks from; "{gift}" is replaced
+    Expression  string   `json:"expression,omitempty"` // file in expressions/ shown while reacting
+    Preference  string   `json:"preference,omitempty"` // "love" (x2), "like" (default) or "dislike" (lowers happiness)
+}
+
+// Effect returns the happiness change of the gift before diminishing returns
+func (g Gift) Effect() int {
+    switch strings.ToLower(g.Preference) {
+    case "love":
+        return g.Happiness * 2
+    case "dislike":
+        return -g.Happiness
+    }
+    return g.Happiness
+}
+
+// Cost returns the Shop price of the gift
+func (g Gift) Cost() int {
+    if g.Price > 0 {
+        return g.Price
+    }
+    return g.Happiness
 }
 
 type GiftsFile struct {
-    Gifts []Gift `json:"gifts"`
+    Gifts        []Gift  `json:"gifts"`
+    RepeatWindow int     `json:"repeatWindow"` // minutes during which the same gift counts as repeated
+    RepeatFactor float64 `json:"repeatFactor"` // happiness multiplier per repeat (1 disables diminishing returns)
 }
 
 var cachedGifts *GiftsFile
@@ -27,14 +53,23 @@ var cachedGifts *GiftsFile
 func DefaultGifts() *GiftsFile {
     return &GiftsFile{
         Gifts: []Gift{
-            {Name: "Chocolate Bar", Happiness: 5},
-			{Name: "Flower Bouquet", Happiness: 10},
-            {Name: "Plushie", Happiness: 15},
-			{Name: "Perfume", Happiness: 15},
-            {Name: "Necklace", Happiness: 20},
-            {Name: "Cute Sticker Pack", Happiness: 3},
-			{Name: "Sketchbook", Happiness: 3},
+            {Name: "Chocolate Bar", Happiness: 5, Price: 5,
+                Reactions: []string{"Chocolate! You know me too well ♥", "Mmm... I'll share. Maybe."}},
+			{Name: "Flower Bouquet", Happiness: 10, Price: 12,
+                Reactions: []string{"They smell wonderful... thank you ♥"}},
+            {Name: "Plushie", Happiness: 15, Price: 20, Preference: "love",
+                Reactions: []string{"So fluffy!! I'm never letting go ♥", "It's adorable! Just like you."}},
+			{Name: "Perfume", Happiness: 15, Price: 22},
+            {Name: "Necklace", Happiness: 20, Price: 35,
+                Reactions: []string{"It's beautiful... will you help me put it on?"}},
+            {Name: "Cute Sticker Pack", Happiness: 3, Price: 3},
+			{Name: "Sketchbook", Happiness: 3, Price: 4, Preference: "love",
+                Reactions: []string{"I'll draw you first!"}},
+            {Name: "Cactus", Happiness: 5, Price: 2, Preference: "dislike", Expression: "confused",
+                Reactions: []string{"A... cactus? Ouch.", "Um. Thanks. I guess it's... pointy."}},
         },
+        RepeatWindow: 10,
+        RepeatFactor: 0.5,
     }
 }
 
@@ -42,7 +77,7 @@ func DefaultGifts() *GiftsFile {
 // FILE CREATION
 // ==============================
 func CreateGiftsFile() error {
-    configDir := filepath.Join(os.Getenv("HOME"), ".config", "cliwaifutamagotchi")
+    configDir := ConfigDir()
     if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
         return fmt.Errorf("failed to create config directory: %w", err)
     }
@@ -76,7 +111,7 @@ func LoadGifts() (*GiftsFile, error) {
         return cachedGifts, nil
     }
 
-    configDir := filepath.Join(os.Getenv("HOME"), ".config", "cliwaifutamagotchi")
+    configDir := ConfigDir()
     giftsPath := filepath.Join(configDir, "gifts.json")
 
     if _, err := os.Stat(giftsPath); os.IsNotExist(err) {
@@ -96,6 +131,13 @@ func LoadGifts() (*GiftsFile, error) {
         // fallback to default if JSON broken or gifts list empty
         gf = *DefaultGifts()
     }
+    // Older gifts.json files don't have these yet
+    if gf.RepeatWindow <= 0 {
+        gf.RepeatWindow = DefaultGifts().RepeatWindow
+    }
+    if gf.RepeatFactor <= 0 || gf.RepeatFactor > 1 {
+        gf.RepeatFactor = DefaultGifts().RepeatFactor
+    }
 
     cachedGifts = &gf
     return cachedGifts, nil
@@ -13,12 +13,29 @@ var (
 	HappinessBarRef  *tview.TextView        // Link to the happiness bar itself so we dynamically update it
 	HeadASCII        *string                // Current head
 	BlinkHeadASCII   *string                // Current blinking head
+	WaifuArtRef      *tview.TextView        // Link to the avatar so the chat can move its mouth
 )
 
 // ==============================
 // Load all expressions once
 // ==============================
 var (
+	neutral       string
+	neutralBlink  string
+	confused      string
+	confusedBlink string
+	bored         string
+	boredBlink    string
+	sad           string
+	sadBlink      string
+	talking       string // optional mouth frame for the typewriter
+	sleepy        string // optional, late at night; eyes closed if missing
+	sleepyBlink   string
+	sleepyTime    bool   // set from calendar.json hours, protected by happinessMutex
+)
+
+// LoadExpressions loads the mood expressions of the current avatar (needs BasePath)
+func LoadExpressions() {
 	neutral       = LoadASCII(BasePath + "/expressions/neutral")
 	neutralBlink  = LoadASCII(BasePath + "/expressions/neutral-blink")
 	confused      = LoadASCII(BasePath + "/expressions/confused")
@@ -27,9 +44,16 @@ var (
 	boredBlink    = LoadASCII(BasePath + "/expressions/bored-blink")
 	sad           = LoadASCII(BasePath + "/expressions/sad")
 	sadBlink      = LoadASCII(BasePath + "/expressions/sad-blink")
-)
+	talking       = LoadOptionalASCII(BasePath+"/expressions/talking", "")
+	sleepy        = LoadOptionalASCII(BasePath+"/expressions/sleepy", neutralBlink)
+	sleepyBlink   = LoadOptionalASCII(BasePath+"/expressions/sleepy-blink", neutralBlink)
+}
 
 func setExpression(head, blink string) {
+	// Late at night a content face is a sleepy one
+	if sleepyTime && head == neutral {
+		head, blink = sleepy, sleepyBlink
+	}
 	if HeadASCII != nil && BlinkHeadASCII != nil && *HeadASCII != head {
 		*HeadASCII = head
 		*BlinkHeadASCII = blink
@@ -60,15 +84,17 @@ func DecreaseHappiness(n int) {
 // ==============================
 func IncreaseHappiness(n int) {
 	happinessMutex.Lock()
-	defer happinessMutex.Unlock()
-
+	gained := 0
 	if Happiness < 1000 {
-		Happiness += n
-		if Happiness > 1000 {
-			Happiness = 1000
-		}
+		gained = min(n, 1000-Happiness)
+		Happiness += gained
 		updateBar()
 	}
+	happinessMutex.Unlock()
+
+	if gained > 0 {
+		TrackStat("happiness", gained)
+	}
 }
 
 // ==============================
@@ -112,6 +138,40 @@ func GetHappinessBar() {
 	}
 }
 
+// ==============================
+// Status items in the bar's title
+// ==============================
+
+// Short texts (coins, streak, ...) shown next to the happiness bar title, in insertion order
+var (
+	statusKeys  []string
+	statusTexts = map[string]string{}
+	statusMutex sync.Mutex
+)
+
+// SetStatusItem sets (or clears with "") one item of the happiness bar title
+func SetStatusItem(key, text string) {
+	statusMutex.Lock()
+	if _, ok := statusTexts[key]; !ok {
+		statusKeys = append(statusKeys, key)
+	}
+	statusTexts[key] = text
+
+	title := "| Happiness Bar |"
+	for _, k := range statusKeys {
+		if statusTexts[k] != "" {
+			title += " " + statusTexts[k] + " |"
+		}
+	}
+	statusMutex.Unlock()
+
+	if HappinessBarRef != nil && UIEventsChan != nil {
+		UIEventsChan <- func() {
+			HappinessBarRef.SetTitle(title)
+		}
+	}
+}
+
 // ==============================
 // Internal UI update
 // ==============================
@@ -0,0 +1,306 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+	"encoding/json"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ==============================
+// KEY BINDING
+// ==============================
+
+// KeyBinding holds every key spec bound to one action.
+// In JSON it is either a single spec ("ctrl+g") or a list (["1", "e"]).
+type KeyBinding []string
+
+// UnmarshalJSON accepts both a string and a list of strings.
+// The specs are checked later by DropInvalid, so that one typo doesn't discard the whole file.
+func (b *KeyBinding) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*b = KeyBinding{single}
+		if single == "" {
+			*b = nil
+		}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		// Kept as written so DropInvalid reports it
+		*b = KeyBinding{string(data)}
+		return nil
+	}
+	*b = KeyBinding(list)
+	return nil
+}
+
+// MarshalJSON writes single bindings as a plain string to keep the file readable, "" when unbound
+func (b KeyBinding) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return json.Marshal("")
+	}
+	if len(b) == 1 {
+		return json.Marshal(b[0])
+	}
+	return json.Marshal([]string(b))
+}
+
+func (b KeyBinding) validate() error {
+	for _, spec := range b {
+		if _, err := ParseKeySpec(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropInvalid removes the bindings with a key that can't be parsed, so their actions
+// keep the default key, and returns a message for each of them, sorted by action id
+func (k KeyBindings) DropInvalid() []string {
+	var problems []string
+	for id, binding := range k {
+		if err := binding.validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("settings.json: %v - %q uses its default key", err, id))
+			delete(k, id)
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// Matches reports whether the event triggers any key of the binding
+func (b KeyBinding) Matches(event *tcell.EventKey) bool {
+	for _, spec := range b {
+		if k, err := ParseKeySpec(spec); err == nil && k.Matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns a short human readable form, e.g. "1/E" or "Ctrl+G"
+func (b KeyBinding) Label() string {
+	labels := make([]string, 0, len(b))
+	for _, spec := range b {
+		if k, err := ParseKeySpec(spec); err == nil {
+			labels = append(labels, k.String())
+		}
+	}
+	return strings.Join(labels, "/")
+}
+
+// ==============================
+// KEY SPEC
+// ==============================
+
+// KeySpec is one parsed key: either a rune or a special tcell key, plus modifiers
+type KeySpec struct {
+	Key  tcell.Key
+	Rune rune
+	Mod  tcell.ModMask
+}
+
+// KeySpecError reports a key spec that could not be understood
+type KeySpecError struct {
+	Spec   string
+	Reason string
+}
+
+func (e *KeySpecError) Error() string {
+	return fmt.Sprintf("invalid key %q: %s", e.Spec, e.Reason)
+}
+
+// Named special keys (lowercase)
+var namedKeys = map[string]tcell.Key{
+	"enter":     tcell.KeyEnter,
+	"return":    tcell.KeyEnter,
+	"esc":       tcell.KeyEscape,
+	"escape":    tcell.KeyEscape,
+	"tab":       tcell.KeyTab,
+	"backtab":   tcell.KeyBacktab,
+	"backspace": tcell.KeyBackspace2,
+	"delete":    tcell.KeyDelete,
+	"del":       tcell.KeyDelete,
+	"insert":    tcell.KeyInsert,
+	"ins":       tcell.KeyInsert,
+	"home":      tcell.KeyHome,
+	"end":       tcell.KeyEnd,
+	"pgup":      tcell.KeyPgUp,
+	"pageup":    tcell.KeyPgUp,
+	"pgdn":      tcell.KeyPgDn,
+	"pagedown":  tcell.KeyPgDn,
+	"up":        tcell.KeyUp,
+	"down":      tcell.KeyDown,
+	"left":      tcell.KeyLeft,
+	"right":     tcell.KeyRight,
+}
+
+// ParseKeySpec parses specs like "q", "é", "F2", "Enter", "ctrl+g" or "alt+shift+x"
+func ParseKeySpec(spec string) (KeySpec, error) {
+	if spec == "" {
+		return KeySpec{}, &KeySpecError{Spec: spec, Reason: "empty key"}
+	}
+	// A single character is always taken literally (this includes "+")
+	if utf8.RuneCountInString(spec) == 1 {
+		r, _ := utf8.DecodeRuneInString(spec)
+		return KeySpec{Key: tcell.KeyRune, Rune: r}, nil
+	}
+
+	parts := strings.Split(spec, "+")
+	name := parts[len(parts)-1]
+	if name == "" {
+		// "ctrl++" means ctrl and the plus key, "ctrl+" lacks the key
+		if !strings.HasSuffix(spec, "++") {
+			return KeySpec{}, &KeySpecError{Spec: spec, Reason: "missing key after the modifiers"}
+		}
+		name = "+"
+		parts = parts[:len(parts)-1]
+	}
+
+	var mod tcell.ModMask
+	for _, m := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(m)) {
+		case "ctrl", "control":
+			mod |= tcell.ModCtrl
+		case "alt", "opt", "option":
+			mod |= tcell.ModAlt
+		case "shift":
+			mod |= tcell.ModShift
+		case "meta", "cmd", "super":
+			mod |= tcell.ModMeta
+		default:
+			return KeySpec{}, &KeySpecError{Spec: spec, Reason: "unknown modifier " + strconv.Quote(m)}
+		}
+	}
+
+	// Single character key with modifiers
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		if mod&tcell.ModCtrl != 0 {
+			lower := unicode.ToLower(r)
+			if lower < 'a' || lower > 'z' {
+				return KeySpec{}, &KeySpecError{Spec: spec, Reason: "ctrl only works with letters"}
+			}
+			return KeySpec{Key: tcell.KeyCtrlA + tcell.Key(lower-'a'), Mod: tcell.ModCtrl}, nil
+		}
+		if mod&tcell.ModShift != 0 {
+			r = unicode.ToUpper(r)
+			mod &^= tcell.ModShift
+		}
+		return KeySpec{Key: tcell.KeyRune, Rune: r, Mod: mod}, nil
+	}
+
+	lower := strings.ToLower(name)
+	if lower == "space" {
+		if mod&tcell.ModCtrl != 0 {
+			return KeySpec{Key: tcell.KeyCtrlSpace, Mod: tcell.ModCtrl}, nil
+		}
+		return KeySpec{Key: tcell.KeyRune, Rune: ' ', Mod: mod}, nil
+	}
+	if key, ok := namedKeys[lower]; ok {
+		return KeySpec{Key: key, Mod: mod}, nil
+	}
+	if strings.HasPrefix(lower, "f") {
+		if n, err := strconv.Atoi(lower[1:]); err == nil && n >= 1 && n <= 64 {
+			return KeySpec{Key: tcell.KeyF1 + tcell.Key(n-1), Mod: mod}, nil
+		}
+	}
+
+	return KeySpec{}, &KeySpecError{Spec: spec, Reason: "unknown key " + strconv.Quote(name)}
+}
+
+// Matches reports whether the event is this key
+func (k KeySpec) Matches(event *tcell.EventKey) bool {
+	// Terminals disagree on shift for special keys, so only ctrl/alt must agree
+	relevant := tcell.ModCtrl | tcell.ModAlt
+	switch {
+	case k.Key == tcell.KeyRune:
+		return event.Key() == tcell.KeyRune && event.Rune() == k.Rune &&
+			event.Modifiers()&relevant == k.Mod&relevant
+	case k.Key >= tcell.KeyCtrlA && k.Key <= tcell.KeyCtrlZ, k.Key == tcell.KeyCtrlSpace:
+		// Control characters carry their modifier in the key itself
+		return event.Key() == k.Key
+	case k.Key == tcell.KeyBackspace2:
+		return event.Key() == tcell.KeyBackspace2 || event.Key() == tcell.KeyBackspace
+	default:
+		return event.Key() == k.Key &&
+			event.Modifiers()&(relevant|tcell.ModShift) == k.Mod&(relevant|tcell.ModShift)
+	}
+}
+
+// String returns the key as shown to the user
+func (k KeySpec) String() string {
+	var mods []string
+	if k.Mod&tcell.ModCtrl != 0 {
+		mods = append(mods, "Ctrl")
+	}
+	if k.Mod&tcell.ModAlt != 0 {
+		mods = append(mods, "Alt")
+	}
+	if k.Mod&tcell.ModShift != 0 {
+		mods = append(mods, "Shift")
+	}
+	if k.Mod&tcell.ModMeta != 0 {
+		mods = append(mods, "Meta")
+	}
+
+	var name string
+	switch {
+	case k.Key == tcell.KeyRune && k.Rune == ' ':
+		name = "Space"
+	case k.Key == tcell.KeyRune:
+		name = string(k.Rune)
+	case k.Key >= tcell.KeyCtrlA && k.Key <= tcell.KeyCtrlZ:
+		name = string(rune('A' + k.Key - tcell.KeyCtrlA))
+	case k.Key == tcell.KeyCtrlSpace:
+		name = "Space"
+	case k.Key == tcell.KeyBackspace2:
+		name = "Backspace"
+	default:
+		name = tcell.KeyNames[k.Key]
+	}
+
+	return strings.Join(append(mods, name), "+")
+}
+
+// ==============================
+// KEY SEQUENCES
+// ==============================
+
+// ParseKeySequence parses keys pressed one after another, e.g. "gg", "G" or "ctrl+w j".
+// A single valid spec is one key; otherwise keys are split on spaces, or taken rune by rune.
+func ParseKeySequence(seq string) ([]KeySpec, error) {
+	if k, err := ParseKeySpec(seq); err == nil {
+		return []KeySpec{k}, nil
+	}
+
+	var parts []string
+	if strings.Contains(seq, " ") {
+		parts = strings.Fields(seq)
+	} else {
+		for _, r := range seq {
+			parts = append(parts, string(r))
+		}
+	}
+	if len(parts) == 0 {
+		return nil, &KeySpecError{Spec: seq, Reason: "empty key"}
+	}
+
+	keys := make([]KeySpec, 0, len(parts))
+	for _, p := range parts {
+		k, err := ParseKeySpec(p)
+		if err != nil {
+			return nil, &KeySpecError{Spec: seq, Reason: err.Error()}
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseKeySpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want KeySpec
+	}{
+		{"q", KeySpec{Key: tcell.KeyRune, Rune: 'q'}},
+		{"é", KeySpec{Key: tcell.KeyRune, Rune: 'é'}},
+		{"+", KeySpec{Key: tcell.KeyRune, Rune: '+'}},
+		{"Enter", KeySpec{Key: tcell.KeyEnter}},
+		{"esc", KeySpec{Key: tcell.KeyEscape}},
+		{"PgDn", KeySpec{Key: tcell.KeyPgDn}},
+		{"F2", KeySpec{Key: tcell.KeyF2}},
+		{"shift+F5", KeySpec{Key: tcell.KeyF5, Mod: tcell.ModShift}},
+		{"ctrl+g", KeySpec{Key: tcell.KeyCtrlG, Mod: tcell.ModCtrl}},
+		{"Ctrl+G", KeySpec{Key: tcell.KeyCtrlG, Mod: tcell.ModCtrl}},
+		{"ctrl+space", KeySpec{Key: tcell.KeyCtrlSpace, Mod: tcell.ModCtrl}},
+		{"alt+x", KeySpec{Key: tcell.KeyRune, Rune: 'x', Mod: tcell.ModAlt}},
+		{"shift+x", KeySpec{Key: tcell.KeyRune, Rune: 'X'}},
+		{"alt++", KeySpec{Key: tcell.KeyRune, Rune: '+', Mod: tcell.ModAlt}},
+		{"Space", KeySpec{Key: tcell.KeyRune, Rune: ' '}},
+	}
+	for _, tt := range tests {
+		got, err := ParseKeySpec(tt.spec)
+		if err != nil {
+			t.Errorf("ParseKeySpec(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseKeySpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseKeySpecErrors(t *testing.T) {
+	for _, spec := range []string{"", "F99", "foo", "hyper+x", "ctrl+1", "ctrl+"} {
+		if got, err := ParseKeySpec(spec); err == nil {
+			t.Errorf("ParseKeySpec(%q) = %+v, want an error", spec, got)
+		}
+	}
+}
+
+func TestKeySpecString(t *testing.T) {
+	for spec, want := range map[string]string{
+		"q":         "q",
+		"ctrl+g":    "Ctrl+G",
+		"alt+x":     "Alt+x",
+		"shift+F5":  "Shift+F5",
+		"space":     "Space",
+		"backspace": "Backspace",
+	} {
+		k, err := ParseKeySpec(spec)
+		if err != nil {
+			t.Fatalf("ParseKeySpec(%q): %v", spec, err)
+		}
+		if got := k.String(); got != want {
+			t.Errorf("%q.String() = %q, want %q", spec, got, want)
+		}
+	}
+}
+
+func TestKeyBindingJSON(t *testing.T) {
+	var keys KeyBindings
+	if err := json.Unmarshal([]byte(`{"encourage": ["1", "e"], "gift": "2", "pet": ""}`), &keys); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(keys["encourage"]) != 2 || len(keys["gift"]) != 1 || keys["pet"] != nil {
+		t.Errorf("unexpected bindings %v", keys)
+	}
+
+	out, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"encourage":["1","e"],"gift":"2","pet":""}`; string(out) != want {
+		t.Errorf("Marshal = %s, want %s", out, want)
+	}
+
+}
+
+func TestKeyBindingsDropInvalid(t *testing.T) {
+	var keys KeyBindings
+	data := `{"quit": "hyper+q", "gift": ["2", "ctrl+"], "pet": true, "encourage": ["1", "e"], "help": ""}`
+	if err := json.Unmarshal([]byte(data), &keys); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	problems := keys.DropInvalid()
+	if len(problems) != 3 {
+		t.Errorf("got problems %q, want one for gift, pet and quit", problems)
+	}
+	for _, id := range []string{"quit", "gift", "pet"} {
+		if _, ok := keys[id]; ok {
+			t.Errorf("%q is still bound to %v", id, keys[id])
+		}
+	}
+	if len(keys["encourage"]) != 2 || keys["help"] != nil {
+		t.Errorf("valid bindings changed: %v", keys)
+	}
+}
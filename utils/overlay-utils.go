@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ==============================
+// MODALS
+// ==============================
+
+// MainPage is the name of the page holding the main grid
+const MainPage = "main"
+
+// focus to restore once the modal is closed
+var focusBeforeModal tview.Primitive
+
+// OverlayOpen reports whether a modal is currently shown over the grid
+func OverlayOpen(pages *tview.Pages) bool {
+	name, _ := pages.GetFrontPage()
+	return name != "" && name != MainPage
+}
+
+// showModal centers `content` over the grid and focuses `focus`
+func showModal(app *tview.Application, pages *tview.Pages, name string,
+	content, focus tview.Primitive, width, height int) {
+
+	if OverlayOpen(pages) {
+		return
+	}
+	focusBeforeModal = app.GetFocus()
+
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	pages.AddPage(name, modal, true, true)
+	app.SetFocus(focus)
+}
+
+// closeModal removes the modal and gives focus back
+func closeModal(app *tview.Application, pages *tview.Pages, name string) {
+	pages.RemovePage(name)
+	if focusBeforeModal != nil {
+		app.SetFocus(focusBeforeModal)
+		focusBeforeModal = nil
+	}
+}
+
+// showTextModal shows `text` in a scrollable modal and returns its view.
+// Closes with Esc, q or the key of `toggle` (the action that opened it).
+func showTextModal(app *tview.Application, pages *tview.Pages, name, title, text string,
+	toggle KeyBinding, width, height int) *tview.TextView {
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetText(text)
+	ApplyTextViewPalette(cachedPaletteOrDefault(), view)
+	view.SetTitle(title)
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || toggle.Matches(event) {
+			closeModal(app, pages, name)
+			return nil
+		}
+		return event
+	})
+
+	showModal(app, pages, name, view, view, width, height)
+	return view
+}
+
+// ==============================
+// HELP OVERLAY
+// ==============================
+
+// ShowHelp lists every action with its keys, plus the navigation keys
+func ShowHelp(app *tview.Application, pages *tview.Pages, settings *Settings) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[::b]Actions[::-]\n")
+	for _, a := range Actions() {
+		keys := settings.Keys.For(a).Label()
+		if keys == "" {
+			keys = "-"
+		}
+		fmt.Fprintf(&b, " %-14s %-18s %s\n", tview.Escape(keys), a.Label, a.Description)
+	}
+
+	fmt.Fprintf(&b, "\n[::b]Navigation[::-]\n")
+	fmt.Fprintf(&b, " %-14s %s\n", "Up/Down", "Move in lists")
+	fmt.Fprintf(&b, " %-14s %s\n", "Enter", "Select")
+	fmt.Fprintf(&b, " %-14s %s\n", "Esc", "Go back / close")
+	if settings.VimNavigation {
+		k := settings.VimKeys
+		fmt.Fprintf(&b, "\n[::b]Vim navigation[::-] (prefix a count, e.g. 3%s)\n", tview.Escape(k.Down))
+		for _, line := range [][2]string{
+			{k.Down + " / " + k.Up, "Move down / up"},
+			{k.Select, "Select"},
+			{k.Back, "Go back"},
+			{k.Top + " / " + k.Bottom, "First / last item (5" + k.Top + ": 5th item)"},
+			{k.Search, "Search in the list"},
+			{k.SearchNext + " / " + k.SearchPrev, "Next / previous match"},
+			{":q", "Quit"},
+		} {
+			fmt.Fprintf(&b, " %-14s %s\n", tview.Escape(line[0]), tview.Escape(line[1]))
+		}
+	}
+
+	help := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(b.String())
+	ApplyTextViewPalette(cachedPaletteOrDefault(), help)
+	help.SetTitle("| Help (Esc to close) |")
+
+	help.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == '?' || event.Rune() == 'q' {
+			closeModal(app, pages, "help")
+			return nil
+		}
+		return event
+	})
+
+	showModal(app, pages, "help", help, help, 76, 22)
+}
+
+// cachedPaletteOrDefault returns the session palette, or the default one before it is loaded
+func cachedPaletteOrDefault() *Palette {
+	if cachedPalette != nil {
+		return cachedPalette
+	}
+	return DefaultPalette()
+}
+
+// ==============================
+// COMMAND PALETTE
+// ==============================
+
+// Command is one entry of the command palette
+type Command struct {
+	Name        string // What is typed, e.g. "gift Plushie"
+	Description string
+	Run         func()
+}
+
+// ShowCommandPalette opens an input with fuzzy matching over the commands
+func ShowCommandPalette(app *tview.Application, pages *tview.Pages, commands []Command) {
+	p := cachedPaletteOrDefault()
+
+	input := tview.NewInputField().
+		SetLabel(":").
+		SetFieldBackgroundColor(tcell.GetColor(p.Background)).
+		SetFieldTextColor(tcell.GetColor(p.Foreground)).
+		SetLabelColor(tcell.GetColor(p.Accent))
+	input.SetBackgroundColor(tcell.GetColor(p.Background))
+
+	list := tview.NewList().ShowSecondaryText(false)
+	ApplyListPalette(p, list)
+	list.SetBorder(false)
+
+	var matches []Command
+	refresh := func(text string) {
+		matches = FilterCommands(commands, text)
+		list.Clear()
+		for _, c := range matches {
+			list.AddItem(tview.Escape(c.Name)+"  [::d]"+tview.Escape(c.Description), "", 0, nil)
+		}
+	}
+	refresh("")
+
+	run := func() {
+		if len(matches) == 0 {
+			return
+		}
+		cmd := matches[list.GetCurrentItem()]
+		closeModal(app, pages, "commands")
+		// Commands may swap the Action Space, so they run once the palette is gone
+		cmd.Run()
+	}
+
+	input.SetChangedFunc(refresh)
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closeModal(app, pages, "commands")
+			return nil
+		case tcell.KeyEnter:
+			run()
+			return nil
+		case tcell.KeyDown, tcell.KeyTab, tcell.KeyCtrlN:
+			if list.GetItemCount() > 0 {
+				list.SetCurrentItem((list.GetCurrentItem() + 1) % list.GetItemCount())
+			}
+			return nil
+		case tcell.KeyUp, tcell.KeyBacktab, tcell.KeyCtrlP:
+			if n := list.GetItemCount(); n > 0 {
+				list.SetCurrentItem((list.GetCurrentItem() - 1 + n) % n)
+			}
+			return nil
+		}
+		return event
+	})
+
+	frame := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(list, 0, 1, false)
+	frame.SetBorder(true).
+		SetTitle("| Commands |").
+		SetBorderColor(tcell.GetColor(p.Border)).
+		SetTitleColor(tcell.GetColor(p.Title)).
+		SetBackgroundColor(tcell.GetColor(p.Background))
+
+	showModal(app, pages, "commands", frame, input, 60, 14)
+}
+
+// FilterCommands returns the commands matching the query, best matches first
+func FilterCommands(commands []Command, query string) []Command {
+	type scored struct {
+		cmd   Command
+		score int
+	}
+
+	var results []scored
+	for _, c := range commands {
+		if score, ok := fuzzyScore(query, c.Name); ok {
+			results = append(results, scored{c, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	out := make([]Command, len(results))
+	for i, r := range results {
+		out[i] = r.cmd
+	}
+	return out
+}
+
+// fuzzyScore matches the query as a subsequence of text (spaces ignored).
+// Consecutive characters and word starts score higher.
+func fuzzyScore(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	score, qi, last := 0, 0, -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		switch {
+		case last == ti-1:
+			score += 5 // consecutive
+		case ti == 0 || !unicode.IsLetter(t[ti-1]):
+			score += 3 // word start
+		default:
+			score -= ti - last // gap
+		}
+		last = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Prefer exact and prefix matches, then shorter names
+	lowered := strings.ToLower(text)
+	if lowered == strings.ToLower(query) {
+		score += 100
+	} else if strings.HasPrefix(lowered, strings.ToLower(query)) {
+		score += 20
+	}
+	return score - len(t)/8, true
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestFuzzyScoreMatches(t *testing.T) {
+	tests := []struct {
+		query, text string
+		want        bool
+	}{
+		{"", "Gift", true},
+		{"gift", "Gift", true},
+		{"GI", "Gift", true},
+		{"ch h", "Chat history", true},
+		{"chy", "Chat history", true},
+		{"tfig", "Gift", false},
+		{"gifts", "Gift", false},
+		{"x", "Gift", false},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.text); ok != tt.want {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", tt.query, tt.text, ok, tt.want)
+		}
+	}
+}
+
+func TestFuzzyScoreRanking(t *testing.T) {
+	// Each query should rank `better` above `worse`
+	tests := []struct {
+		query, better, worse string
+	}{
+		{"gift", "Gift", "Gift shop"},            // exact before prefix
+		{"sh", "Shop", "Push"},                   // prefix before a later match
+		{"ch", "Chat history", "Launch"},         // word start before mid-word
+		{"st", "Stats", "Shop settings"},         // consecutive before gaps
+		{"pe", "Pet", "Pet her for a long time"}, // shorter names win ties
+	}
+	for _, tt := range tests {
+		better, ok1 := fuzzyScore(tt.query, tt.better)
+		worse, ok2 := fuzzyScore(tt.query, tt.worse)
+		if !ok1 || !ok2 {
+			t.Errorf("%q should match both %q and %q", tt.query, tt.better, tt.worse)
+			continue
+		}
+		if better <= worse {
+			t.Errorf("%q: %q scored %d, not above %q (%d)", tt.query, tt.better, better, tt.worse, worse)
+		}
+	}
+}
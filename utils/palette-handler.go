@@ -45,9 +45,9 @@ func DefaultPalette() *Palette {
 // FILE HANDLING
 // ==============================
 
-// CreatePaletteFile creates palette.json in ~/.config/cliwaifutamagotchi if missing
+// CreatePaletteFile creates palette.json in the profile config directory if missing
 func CreatePaletteFile() error {
-	configDir := filepath.Join(os.Getenv("HOME"), ".config", "cliwaifutamagotchi")
+	configDir := ConfigDir()
 	if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -79,7 +79,7 @@ func LoadPalette() (*Palette, error) {
 		return cachedPalette, nil
 	}
 
-	configDir := filepath.Join(os.Getenv("HOME"), ".config", "cliwaifutamagotchi")
+	configDir := ConfigDir()
 	palettePath := filepath.Join(configDir, "palette.json")
 
 	if _, err := os.Stat(palettePath); os.IsNotExist(err) {
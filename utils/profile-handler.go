@@ -0,0 +1,314 @@
+package utils
+
+import (
+	"os"
+	"io"
+	"fmt"
+	"sort"
+	"errors"
+	"strings"
+	"path/filepath"
+
+	"github.com/rivo/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ==============================
+// PROFILES
+// ==============================
+
+// DefaultProfile is the profile stored directly in the config root
+const DefaultProfile = "default"
+
+// ActiveProfile is the profile used for this session ("" means default)
+var ActiveProfile string
+
+// ConfigRoot returns ~/.config/cliwaifutamagotchi
+func ConfigRoot() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "cliwaifutamagotchi")
+}
+
+// ConfigDir returns the config directory of the active profile
+func ConfigDir() string {
+	return profileDir(ActiveProfile)
+}
+
+// profileDir returns the directory of a profile; default lives in the root
+func profileDir(name string) string {
+	if name == "" || name == DefaultProfile {
+		return ConfigRoot()
+	}
+	return filepath.Join(ConfigRoot(), "profiles", name)
+}
+
+// SetProfile selects the profile used by every config and save file
+func SetProfile(name string) error {
+	if name == "" || name == DefaultProfile {
+		ActiveProfile = ""
+		return nil
+	}
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if !profileExists(name) {
+		return fmt.Errorf("profile %q does not exist (create it with `cliwt profile create %s`)", name, name)
+	}
+	ActiveProfile = name
+	return nil
+}
+
+// validateProfileName keeps profile names usable as directory names
+func validateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if name == DefaultProfile {
+		return fmt.Errorf("%q is reserved", DefaultProfile)
+	}
+	for _, r := range name {
+		ok := r == '-' || r == '_' || r == '.' ||
+			(r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !ok {
+			return fmt.Errorf("invalid profile name %q: use letters, digits, '-', '_' or '.'", name)
+		}
+	}
+	if strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid profile name %q: must not start with '.'", name)
+	}
+	return nil
+}
+
+func profileExists(name string) bool {
+	if name == "" || name == DefaultProfile {
+		return true
+	}
+	info, err := os.Stat(profileDir(name))
+	return err == nil && info.IsDir()
+}
+
+// ==============================
+// PROFILE MANAGEMENT
+// ==============================
+
+// ListProfiles returns the default profile followed by named profiles
+func ListProfiles() ([]string, error) {
+	profiles := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(filepath.Join(ConfigRoot(), "profiles"))
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && validateProfileName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return append(profiles, names...), nil
+}
+
+// CreateProfile creates an empty profile; its files are created on first run
+func CreateProfile(name string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if profileExists(name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	if err := os.MkdirAll(profileDir(name), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	return nil
+}
+
+// DeleteProfile removes a named profile and all of its files
+func DeleteProfile(name string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if !profileExists(name) {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+	if err := os.RemoveAll(profileDir(name)); err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+	return nil
+}
+
+// CopyProfile copies every config and save file of src into a new profile dst.
+// The files are copied into a hidden directory renamed to dst at the end,
+// so a copy that fails halfway doesn't leave a profile behind.
+func CopyProfile(src, dst string) error {
+	if !profileExists(src) {
+		return fmt.Errorf("profile %q does not exist", src)
+	}
+	if err := validateProfileName(dst); err != nil {
+		return err
+	}
+	if profileExists(dst) {
+		return fmt.Errorf("profile %q already exists", dst)
+	}
+
+	entries, err := os.ReadDir(profileDir(src))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read profile %q: %w", src, err)
+	}
+
+	// Names starting with '.' are never listed as profiles
+	profiles := filepath.Join(ConfigRoot(), "profiles")
+	if err := os.MkdirAll(profiles, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(profiles, "."+dst+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	defer os.RemoveAll(tmp) // nothing left to remove once renamed
+
+	for _, e := range entries {
+		// Only plain files: this also skips "profiles/" when copying default
+		if !e.Type().IsRegular() {
+			continue
+		}
+		from := filepath.Join(profileDir(src), e.Name())
+		if err := copyFile(from, filepath.Join(tmp, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	if err := os.Rename(tmp, profileDir(dst)); err != nil {
+		return fmt.Errorf("failed to create profile %q: %w", dst, err)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	// A full disk may only show up when the file is closed
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return nil
+}
+
+// ==============================
+// PROFILE SUBCOMMAND
+// ==============================
+
+const profileUsage = "usage: cliwt profile list | create NAME | delete NAME | copy SRC DST"
+
+// RunProfileCommand handles `cliwt profile ...`
+func RunProfileCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(profileUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		profiles, err := ListProfiles()
+		if err != nil {
+			return err
+		}
+		for _, p := range profiles {
+			fmt.Println(p)
+		}
+		return nil
+	case "create":
+		if len(args) != 2 {
+			return errors.New(profileUsage)
+		}
+		if err := CreateProfile(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Created profile %q\n", args[1])
+		return nil
+	case "delete":
+		if len(args) != 2 {
+			return errors.New(profileUsage)
+		}
+		if err := DeleteProfile(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted profile %q\n", args[1])
+		return nil
+	case "copy":
+		if len(args) != 3 {
+			return errors.New(profileUsage)
+		}
+		if err := CopyProfile(args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Printf("Copied profile %q to %q\n", args[1], args[2])
+		return nil
+	}
+
+	return fmt.Errorf("unknown profile command %q\n%s", args[0], profileUsage)
+}
+
+// ==============================
+// PROFILE PICKER
+// ==============================
+
+// PickProfile shows a small picker when named profiles exist.
+// Returns ok=false if the user closed the picker without choosing.
+func PickProfile() (string, bool, error) {
+	profiles, err := ListProfiles()
+	if err != nil {
+		return "", false, err
+	}
+	// Nothing to pick from
+	if len(profiles) == 1 {
+		return DefaultProfile, true, nil
+	}
+
+	app := tview.NewApplication()
+	list := tview.NewList().ShowSecondaryText(false)
+	ApplyListPalette(DefaultPalette(), list)
+	list.SetTitle("| Profiles |").SetTitleAlign(tview.AlignCenter)
+
+	var picked string
+	for _, p := range profiles {
+		name := p
+		list.AddItem("- "+name, "", 0, func() {
+			picked = name
+			app.Stop()
+		})
+	}
+	list.SetDoneFunc(func() {
+		app.Stop()
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' {
+			app.Stop()
+			return nil
+		}
+		return event
+	})
+
+	if err := app.SetRoot(list, true).Run(); err != nil {
+		return "", false, err
+	}
+	return picked, picked != "", nil
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"path/filepath"
+)
+
+func TestCopyProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := ConfigRoot()
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "save.json"), []byte(`{"coins": 7}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CopyProfile(DefaultProfile, "work"); err != nil {
+		t.Fatalf("CopyProfile: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(profileDir("work"), "save.json"))
+	if err != nil || string(data) != `{"coins": 7}` {
+		t.Errorf("copied save.json = %q, %v", data, err)
+	}
+
+	// The temporary directory is gone and only the new profile is listed
+	entries, err := os.ReadDir(filepath.Join(root, "profiles"))
+	if err != nil || len(entries) != 1 || entries[0].Name() != "work" {
+		t.Errorf("profiles/ holds %v, %v", entries, err)
+	}
+	if profiles, _ := ListProfiles(); !slices.Equal(profiles, []string{DefaultProfile, "work"}) {
+		t.Errorf("ListProfiles = %q", profiles)
+	}
+
+	for _, dst := range []string{"work", DefaultProfile, ".hidden", ""} {
+		if err := CopyProfile(DefaultProfile, dst); err == nil {
+			t.Errorf("CopyProfile to %q succeeded", dst)
+		}
+	}
+	if err := CopyProfile("missing", "other"); err == nil || profileExists("other") {
+		t.Errorf("copying a missing profile: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// RELATIONSHIP
+// ==============================
+
+// RelationshipLevel is reached once the XP gets to `XP`
+type RelationshipLevel struct {
+	Level  int
+	XP     int
+	Title  string // shown next to her name
+	Outfit string // exclusive outfit unlocked, relative to clothes/
+}
+
+var relationshipLevels = []RelationshipLevel{
+	{Level: 1, XP: 0, Title: "Stranger"},
+	{Level: 2, XP: 50, Title: "Acquaintance"},
+	{Level: 3, XP: 150, Title: "Friend", Outfit: "exclusive/mint-pe-uniform"},
+	{Level: 4, XP: 400, Title: "Close Friend"},
+	{Level: 5, XP: 900, Title: "Best Friend", Outfit: "exclusive/lavender-hoodie"},
+	{Level: 6, XP: 2000, Title: "Sweetheart"},
+	{Level: 7, XP: 4000, Title: "Soulmate", Outfit: "exclusive/starlight-coat"},
+}
+
+// statXP is the XP earned each time a stat is tracked
+var statXP = map[string]int{
+	"encouragements": 2,
+	"gifts":          5,
+	"games":          3,
+	"focus":          10,
+	"talks":          1,
+	"pets":           1,
+	"checkIns":       10,
+}
+
+var (
+	// levelUpNotify shows a new level, set by StartRelationship (UI goroutine only)
+	levelUpNotify func(l RelationshipLevel)
+	// name shown in the border of the avatar, before the title
+	relationshipName string
+)
+
+// XP returns the relationship XP
+func XP() int {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return currentSave().XP
+}
+
+// levelFor returns the highest level reached with `xp`
+func levelFor(xp int) RelationshipLevel {
+	level := relationshipLevels[0]
+	for _, l := range relationshipLevels {
+		if xp >= l.XP {
+			level = l
+		}
+	}
+	return level
+}
+
+// CurrentLevel returns the current relationship level
+func CurrentLevel() RelationshipLevel {
+	return levelFor(XP())
+}
+
+// nextLevel returns the level after `l`, or false at the last one
+func nextLevel(l RelationshipLevel) (RelationshipLevel, bool) {
+	if l.Level >= len(relationshipLevels) {
+		return l, false
+	}
+	return relationshipLevels[l.Level], true
+}
+
+// AddXP grows the relationship and shows a level up
+func AddXP(n int) {
+	if n <= 0 {
+		return
+	}
+	saveMutex.Lock()
+	s := currentSave()
+	before := levelFor(s.XP)
+	s.XP += n
+	after := levelFor(s.XP)
+	saveMutex.Unlock()
+
+	if after.Level == before.Level {
+		return
+	}
+	for _, l := range relationshipLevels[before.Level:after.Level] {
+		if l.Outfit != "" {
+			UnlockOutfit(l.Outfit)
+		}
+	}
+	if levelUpNotify != nil && UIEventsChan != nil {
+		UIEventsChan <- func() {
+			levelUpNotify(after)
+		}
+	}
+}
+
+// relationshipProgress describes the level and the XP left to the next one
+func relationshipProgress() string {
+	xp := XP()
+	level := levelFor(xp)
+	next, ok := nextLevel(level)
+	if !ok {
+		return fmt.Sprintf("[::b]Level %d - %s[::-]  %d XP", level.Level, level.Title, xp)
+	}
+	return fmt.Sprintf("[::b]Level %d - %s[::-]  %s %d/%d XP to %s",
+		level.Level, level.Title, progressBar(xp-level.XP, next.XP-level.XP, 20), xp, next.XP, next.Title)
+}
+
+// relationshipTitle is the border title of the avatar: her name and the level title
+func relationshipTitle() string {
+	return fmt.Sprintf("| %s - %s |", relationshipName, CurrentLevel().Title)
+}
+
+// StartRelationship shows the level title next to her name and celebrates level ups
+func StartRelationship(
+	waifuArt, chatBox *tview.TextView,
+	head *string,
+	happyHead, waifuName string,
+	currentBody *string,
+) {
+	relationshipName = waifuName
+	waifuArt.SetTitle(relationshipTitle())
+
+	// Levels reached before their outfit existed
+	for _, l := range relationshipLevels[:CurrentLevel().Level] {
+		if l.Outfit != "" {
+			UnlockOutfit(l.Outfit)
+		}
+	}
+
+	levelUpNotify = func(l RelationshipLevel) {
+		line := fmt.Sprintf("%s: We're closer now... Level %d: %s ♥", waifuName, l.Level, l.Title)
+		if l.Outfit != "" {
+			line += " (new outfit: " + l.Outfit + ")"
+		}
+		SetChat(chatBox, line)
+		waifuArt.SetTitle(relationshipTitle())
+		waifuArt.SetText(happyHead + "\n" + *currentBody)
+		time.AfterFunc(3*time.Second, func() {
+			UIEventsChan <- func() {
+				waifuArt.SetText(*head + "\n" + *currentBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "encoding/json"
+    "path/filepath"
+)
+
+// ==============================
+// REMINDERS STRUCT
+// ==============================
+type Reminder struct {
+    Name       string `json:"name"`
+    Message    string `json:"message"`              // chat line, with {variables}
+    Every      int    `json:"every"`                // minutes between two reminders
+    When       string `json:"when,omitempty"`       // same tags as encouragements, e.g. "weekday"
+    Expression string `json:"expression,omitempty"` // file in expressions/ shown with the message
+
+    conditions Conditions
+}
+
+type RemindersFile struct {
+    Enabled       bool       `json:"enabled"`       // off until the user opts in
+    Reminders     []Reminder `json:"reminders"`
+    AckWindow     int        `json:"ackWindow"`     // minutes to acknowledge before it counts as missed
+    MissedPenalty int        `json:"missedPenalty"` // happiness lost for a missed reminder
+}
+
+var cachedReminders *RemindersFile
+
+// ==============================
+// DEFAULT REMINDERS
+// ==============================
+func DefaultReminders() *RemindersFile {
+    return &RemindersFile{
+        Reminders: []Reminder{
+            {Name: "Stand up", Message: "Hey {user}, time to stand up and stretch a little!", Every: 50, Expression: "talking"},
+            {Name: "Water", Message: "Drink some water! I'll wait ♥", Every: 40, Expression: "talking"},
+            {Name: "Eyes", Message: "Eye break: look at something far away for 20 seconds.", Every: 20, Expression: "talking"},
+        },
+        AckWindow:     5,
+        MissedPenalty: 0,
+    }
+}
+
+// ==============================
+// FILE CREATION
+// ==============================
+func CreateRemindersFile() error {
+    configDir := ConfigDir()
+    if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+        return fmt.Errorf("failed to create config directory: %w", err)
+    }
+
+    remindersPath := filepath.Join(configDir, "reminders.json")
+
+    if _, err := os.Stat(remindersPath); err == nil {
+        return nil
+    }
+
+    file, err := os.Create(remindersPath)
+    if err != nil {
+        return fmt.Errorf("failed to create reminders file: %w", err)
+    }
+    defer file.Close()
+
+    encoder := json.NewEncoder(file)
+    encoder.SetIndent("", "  ")
+    if err := encoder.Encode(DefaultReminders()); err != nil {
+        return fmt.Errorf("failed to write default reminders: %w", err)
+    }
+
+    return nil
+}
+
+// ==============================
+// LOAD REMINDERS
+// ==============================
+func LoadReminders() (*RemindersFile, error) {
+    if cachedReminders != nil {
+        return cachedReminders, nil
+    }
+
+    configDir := ConfigDir()
+    remindersPath := filepath.Join(configDir, "reminders.json")
+
+    if _, err := os.Stat(remindersPath); os.IsNotExist(err) {
+        if err := CreateRemindersFile(); err != nil {
+            return nil, err
+        }
+    }
+
+    file, err := os.Open(remindersPath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open reminders file: %w", err)
+    }
+    defer file.Close()
+
+    var rf RemindersFile
+    if err := json.NewDecoder(file).Decode(&rf); err != nil {
+        // fallback to default if JSON broken
+        rf = *DefaultReminders()
+    }
+    if rf.AckWindow <= 0 {
+        rf.AckWindow = DefaultReminders().AckWindow
+    }
+    if rf.MissedPenalty < 0 {
+        rf.MissedPenalty = 0
+    }
+
+    // Reminders without a schedule or with unknown tags are skipped
+    valid := rf.Reminders[:0]
+    for _, r := range rf.Reminders {
+        conditions, unknown := ParseConditions(strings.Fields(r.When))
+        if r.Every <= 0 || len(unknown) > 0 {
+            continue
+        }
+        r.conditions = conditions
+        valid = append(valid, r)
+    }
+    rf.Reminders = valid
+
+    cachedReminders = &rf
+    return cachedReminders, nil
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// REMINDERS
+// ==============================
+
+// dueReminder is a reminder that was shown and waits for an acknowledgement
+type dueReminder struct {
+	reminder Reminder
+	shownAt  time.Time
+}
+
+var (
+	reminderMutex   sync.Mutex
+	pendingReminder *dueReminder
+)
+
+// ReminderPending reports whether a reminder waits to be acknowledged
+func ReminderPending() bool {
+	reminderMutex.Lock()
+	defer reminderMutex.Unlock()
+	return pendingReminder != nil
+}
+
+// StartReminders schedules the reminders of reminders.json on the tick loop.
+// Only one is shown at a time; one not acknowledged within "ackWindow" minutes is missed.
+// `ackKey` is the key shown to acknowledge it.
+func StartReminders(
+	waifuArt, chatBox *tview.TextView,
+	head *string,
+	waifuName string,
+	currentBody *string,
+	ackKey string,
+) error {
+	rf, err := LoadReminders()
+	if err != nil {
+		return err
+	}
+	if !rf.Enabled || len(rf.Reminders) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	next := make([]time.Time, len(rf.Reminders))
+	for i, r := range rf.Reminders {
+		next[i] = start.Add(time.Duration(r.Every) * time.Minute)
+	}
+	ackWindow := time.Duration(rf.AckWindow) * time.Minute
+
+	OnTick(func(now time.Time) {
+		ctx := CurrentContext()
+
+		// Only the state changes under the lock: the UI goroutine takes it too (ReminderPending),
+		// so sending on UIEventsChan while holding it could deadlock
+		var missed, due *dueReminder
+		reminderMutex.Lock()
+		if p := pendingReminder; p != nil {
+			if now.Sub(p.shownAt) >= ackWindow {
+				missed, pendingReminder = p, nil
+			}
+		} else {
+			for i, r := range rf.Reminders {
+				if now.Before(next[i]) {
+					continue
+				}
+				next[i] = now.Add(time.Duration(r.Every) * time.Minute)
+				if !r.conditions.Match(ctx) {
+					continue
+				}
+				due = &dueReminder{reminder: r, shownAt: now}
+				pendingReminder = due
+				break // one at a time, the others wait for the next tick
+			}
+		}
+		reminderMutex.Unlock()
+
+		switch {
+		case missed != nil:
+			SetStatusItem("reminder", "")
+			DecreaseHappiness(rf.MissedPenalty)
+			UIEventsChan <- func() {
+				SetChat(chatBox, fmt.Sprintf("%s: You missed my reminder (%s)... please take care of yourself.",
+					waifuName, missed.reminder.Name))
+			}
+		case due != nil:
+			r := due.reminder
+			SetStatusItem("reminder", "! "+r.Name)
+
+			line := RenderTemplate(r.Message, ctx)
+			face := LoadOptionalASCII(BasePath+"/expressions/"+r.Expression, confused)
+			UIEventsChan <- func() {
+				SetChat(chatBox, fmt.Sprintf("%s: %s [%s]", waifuName, line, ackKey))
+				waifuArt.SetText(face + "\n" + *currentBody)
+				time.AfterFunc(3*time.Second, func() {
+					UIEventsChan <- func() {
+						waifuArt.SetText(*head + "\n" + *currentBody)
+					}
+				})
+			}
+		}
+	})
+	return nil
+}
+
+// AcknowledgeReminder marks the shown reminder as done
+func AcknowledgeReminder(chatBox *tview.TextView, waifuName string) {
+	reminderMutex.Lock()
+	p := pendingReminder
+	pendingReminder = nil
+	reminderMutex.Unlock()
+
+	if p == nil {
+		return
+	}
+	SetStatusItem("reminder", "")
+	IncreaseHappiness(2)
+	SetChat(chatBox, waifuName+": Thank you for listening ♥")
+}
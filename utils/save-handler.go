@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"os"
+	"fmt"
+	"sync"
+	"time"
+	"encoding/json"
+	"path/filepath"
+)
+
+// ==============================
+// SAVE STRUCT
+// ==============================
+
+// SaveState stores the progress that should survive between sessions
+type SaveState struct {
+	Happiness int            `json:"happiness"`
+	LastSeen  time.Time      `json:"lastSeen"`
+	Coins     int            `json:"coins"`
+	Inventory map[string]int `json:"inventory"` // gift name -> owned quantity
+
+	RecentGifts          map[string][]time.Time `json:"recentGifts,omitempty"` // for diminishing returns
+	EncouragementHistory []string               `json:"encouragementHistory,omitempty"`
+	ChatLog              []ChatEntry            `json:"chatLog,omitempty"` // last "chatHistory" messages
+	HighScores           map[string]int         `json:"highScores,omitempty"` // mini-game id -> best score
+	FocusStats           map[string]FocusDay    `json:"focusStats,omitempty"` // date -> focus sessions
+
+	LastCheckIn     string   `json:"lastCheckIn,omitempty"` // date of the last daily check-in
+	Streak          int      `json:"streak"`                // days in a row with a check-in
+	BestStreak      int      `json:"bestStreak"`
+	UnlockedOutfits []string `json:"unlockedOutfits,omitempty"` // exclusive outfits earned
+
+	Stats        map[string]int       `json:"stats,omitempty"`        // counters followed by achievements
+	OutfitsWorn  []string             `json:"outfitsWorn,omitempty"`  // every outfit worn at least once
+	Achievements map[string]time.Time `json:"achievements,omitempty"` // achievement id -> unlock time
+	XP           int                  `json:"xp"`                     // relationship XP
+
+	GiftsGiven      map[string]int `json:"giftsGiven,omitempty"`  // gift name -> times given
+	MoodSeconds     map[string]int `json:"moodSeconds,omitempty"` // mood -> seconds spent in it while open
+	LowestHappiness int            `json:"lowestHappiness"`
+	LastSpecialDay  string         `json:"lastSpecialDay,omitempty"` // date of the last weekend/holiday line
+	EventLog        []EventEntry   `json:"eventLog,omitempty"`       // last random events
+
+	LastOutfit      string         `json:"lastOutfit,omitempty"`      // put back on at launch
+	FavoriteOutfits []string       `json:"favoriteOutfits,omitempty"` // shown first in the wardrobe
+	OutfitWears     map[string]int `json:"outfitWears,omitempty"`     // outfit -> times put on
+	WardrobeSort    string         `json:"wardrobeSort,omitempty"`    // "name" or "worn"
+
+	TaggedEncouragements bool `json:"taggedEncouragements,omitempty"` // tagged default lines added to an older words-of-encouragement.txt
+}
+
+// cachedSave stores the save loaded for this session
+var (
+	cachedSave *SaveState
+	saveMutex  sync.Mutex
+)
+
+// ==============================
+// DEFAULT SAVE
+// ==============================
+
+// DefaultSave returns the state of a brand new companion
+func DefaultSave() *SaveState {
+	return &SaveState{
+		Happiness: 1000,
+		Coins:     20, // enough for a first gift
+		Inventory: map[string]int{},
+
+		LowestHappiness: 1000,
+	}
+}
+
+// ==============================
+// FILE HANDLING
+// ==============================
+
+func savePath() string {
+	return filepath.Join(ConfigDir(), "save.json")
+}
+
+// LoadSave loads save.json of the active profile (or default if missing), cached for session
+func LoadSave() (*SaveState, error) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	if cachedSave != nil {
+		return cachedSave, nil
+	}
+
+	s := DefaultSave()
+	file, err := os.Open(savePath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to open save file: %w", err)
+	}
+	if err == nil {
+		defer file.Close()
+		if err := json.NewDecoder(file).Decode(s); err != nil {
+			// Broken save: start over instead of refusing to launch
+			s = DefaultSave()
+		}
+	}
+	if s.Inventory == nil {
+		s.Inventory = map[string]int{}
+	}
+
+	cachedSave = s
+	return cachedSave, nil
+}
+
+// currentSave returns the session save; callers must hold saveMutex
+func currentSave() *SaveState {
+	if cachedSave == nil {
+		cachedSave = DefaultSave()
+	}
+	return cachedSave
+}
+
+// AutoSave writes the save every `interval` from the main loop, so a crash loses little.
+// A failing write is shown on the status line until a later save succeeds
+func AutoSave(interval time.Duration) {
+	var last time.Time
+	failing := false
+	OnTick(func(now time.Time) {
+		if last.IsZero() {
+			last = now
+			return
+		}
+		if now.Sub(last) < interval {
+			return
+		}
+		last = now
+		if err := WriteSave(); err != nil {
+			if !failing {
+				SetStatusItem("save", "⚠ save failed")
+			}
+			failing = true
+		} else if failing {
+			SetStatusItem("save", "")
+			failing = false
+		}
+	})
+}
+
+// WriteSave stores the current session state into save.json
+func WriteSave() error {
+	happinessMutex.Lock()
+	happiness := Happiness
+	happinessMutex.Unlock()
+
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	currentSave().Happiness = happiness
+	cachedSave.LastSeen = time.Now()
+
+	if err := os.MkdirAll(ConfigDir(), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a half-written save
+	tmpPath := savePath() + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create save file: %w", err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(cachedSave); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, savePath()); err != nil {
+		return fmt.Errorf("failed to replace save file: %w", err)
+	}
+	return nil
+}
@@ -10,44 +10,128 @@ import (
 // ==============================
 // SETTINGS STRUCT
 // ==============================
-type KeyBindings struct {
-    Encourage      string `json:"encourage"`
-    Gift           string `json:"gift"`
-    DressUp        string `json:"dressup"`
-    PoseMode       string `json:"poseMode"`
-    BackgroundMode string `json:"backgroundMode"`
-    SwapGender     string `json:"swapGender"`
-    Quit           string `json:"quit"`
+// KeyBindings maps action ids to their keys; missing actions use their default key
+type KeyBindings map[string]KeyBinding
+
+// VimKeys are key sequences ("gg", "ctrl+w j") for the vim navigation layer
+type VimKeys struct {
+    Down       string `json:"down"`
+    Up         string `json:"up"`
+    Select     string `json:"select"`
+    Back       string `json:"back"`
+    Top        string `json:"top"`
+    Bottom     string `json:"bottom"`
+    Search     string `json:"search"`
+    SearchNext string `json:"searchNext"`
+    SearchPrev string `json:"searchPrev"`
+}
+
+// ChatBot is an optional local language model answering in Talk
+type ChatBot struct {
+    Enabled  bool   `json:"enabled"`
+    Endpoint string `json:"endpoint"` // OpenAI compatible, e.g. llama.cpp or Ollama
+    Model    string `json:"model"`
+    Timeout  int    `json:"timeout"`  // seconds to wait for a whole reply
+}
+
+// Focus configures the work/break cycles of the Focus action (minutes)
+type Focus struct {
+    Work       int `json:"work"`
+    ShortBreak int `json:"shortBreak"`
+    LongBreak  int `json:"longBreak"`
+    Cycles     int `json:"cycles"`    // work sessions before a long break
+    Happiness  int `json:"happiness"` // earned per completed work session
+    Coins      int `json:"coins"`     // earned per completed work session
+}
+
+// Wishes configures the gifts and outfits she asks for
+type Wishes struct {
+    Enabled   bool `json:"enabled"`
+    Every     int  `json:"every"`     // average minutes between two wishes
+    Timeout   int  `json:"timeout"`   // minutes to fulfill one
+    Happiness int  `json:"happiness"` // bonus for a fulfilled wish
+    XP        int  `json:"xp"`        // bonus for a fulfilled wish
+    Penalty   int  `json:"penalty"`   // happiness lost for an ignored wish
 }
 
 type Settings struct {
     Name           string      `json:"name"`
+    UserName       string      `json:"userName"` // what she calls you ({user}); $USER if empty
     DefaultMessage string      `json:"defaultMessage"`
     VimNavigation  bool        `json:"vimNavigation"`
+    VimKeys        VimKeys     `json:"vimKeys"`
+    VimTimeout     int         `json:"vimTimeout"` // ms to wait for the rest of a sequence or count
     AvatarType     string      `json:"avatarType"`
+    CoinsPerMinute int         `json:"coinsPerMinute"` // coins earned while the app is open
+    Mouse          bool        `json:"mouse"`
+    PetCooldown    int         `json:"petCooldown"` // seconds between two pets
+    ChatHistory    int         `json:"chatHistory"` // messages kept in the chat log
+    Typewriter     bool        `json:"typewriter"`
+    TypewriterSpeed int        `json:"typewriterSpeed"` // ms per character
+    ChatBot        ChatBot     `json:"chatBot"`
+    Focus          Focus       `json:"focus"`
+    Wishes         Wishes      `json:"wishes"`
     Keys           KeyBindings `json:"keys"`
+
+    Warnings []string `json:"-"` // problems found while loading, reported at launch
 }
 
 var cachedSettings *Settings
 
+// Key ids of older settings.json files whose actions are gone
+var legacyKeyActions = []string{"poseMode", "swapGender"}
+
 // ==============================
 // DEFAULT SETTINGS
 // ==============================
 func DefaultSettings() *Settings {
     return &Settings{
         Name:           "Waifu",
+        UserName:       "",
         DefaultMessage: "...",
         VimNavigation:  false,
+        VimKeys: VimKeys{
+            Down:       "j",
+            Up:         "k",
+            Select:     "l",
+            Back:       "h",
+            Top:        "gg",
+            Bottom:     "G",
+            Search:     "/",
+            SearchNext: "n",
+            SearchPrev: "N",
+        },
+        VimTimeout:     800,
         AvatarType:     "waifu",
-        Keys: KeyBindings{
-            Encourage:      "1",
-            Gift:           "2",
-            DressUp:        "3",
-            PoseMode:       "4",
-            BackgroundMode: "b",
-            SwapGender:     "s",
-            Quit:           "q",
+        CoinsPerMinute: 1,
+        Mouse:          false,
+        PetCooldown:    3,
+        ChatHistory:    100,
+        Typewriter:     false,
+        TypewriterSpeed: 30,
+        ChatBot: ChatBot{
+            Enabled:  false,
+            Endpoint: "http://localhost:11434/v1/chat/completions",
+            Model:    "llama3.2",
+            Timeout:  60,
+        },
+        Focus: Focus{
+            Work:       25,
+            ShortBreak: 5,
+            LongBreak:  15,
+            Cycles:     4,
+            Happiness:  15,
+            Coins:      10,
         },
+        Wishes: Wishes{
+            Enabled:   true,
+            Every:     45,
+            Timeout:   15,
+            Happiness: 40,
+            XP:        15,
+            Penalty:   10,
+        },
+        Keys: DefaultKeyBindings(), // empty until the actions are registered
     }
 }
 
@@ -55,7 +139,7 @@ func DefaultSettings() *Settings {
 // FILE HANDLING
 // ==============================
 func CreateSettingsFile() error {
-    configDir := filepath.Join(os.Getenv("HOME"), ".config", "cliwaifutamagotchi")
+    configDir := ConfigDir()
     if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
         return fmt.Errorf("failed to create config directory: %w", err)
     }
@@ -86,13 +170,15 @@ func LoadSettings() (*Settings, error) {
         return cachedSettings, nil
     }
 
-    configDir := filepath.Join(os.Getenv("HOME"), ".config", "cliwaifutamagotchi")
+    configDir := ConfigDir()
     settingsPath := filepath.Join(configDir, "settings.json")
 
+    // A missing file is only written by CreateSettingsFile once the actions are registered,
+    // so that its "keys" list every one of them
     if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
-        if err := CreateSettingsFile(); err != nil {
-            return nil, err
-        }
+        s := *DefaultSettings()
+        cachedSettings = &s
+        return cachedSettings, nil
     }
 
     file, err := os.Open(settingsPath)
@@ -101,10 +187,16 @@ func LoadSettings() (*Settings, error) {
     }
     defer file.Close()
 
-    var s Settings
+    // Start from defaults so keys missing in the file keep working
+    s := *DefaultSettings()
     if err := json.NewDecoder(file).Decode(&s); err != nil {
         s = *DefaultSettings()
     }
+    for _, id := range legacyKeyActions {
+        delete(s.Keys, id)
+    }
+    // A typo in a key is worth reporting instead of silently resetting everything
+    s.Warnings = s.Keys.DropInvalid()
 
     cachedSettings = &s
     return cachedSettings, nil
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"os"
+	"testing"
+	"path/filepath"
+)
+
+// loadSettingsFile loads `data` as the settings.json of a temporary home
+func loadSettingsFile(t *testing.T, data string) *Settings {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	previous, profile := cachedSettings, ActiveProfile
+	cachedSettings, ActiveProfile = nil, ""
+	t.Cleanup(func() { cachedSettings, ActiveProfile = previous, profile })
+
+	if err := os.MkdirAll(ConfigDir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ConfigDir(), "settings.json"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	return s
+}
+
+func TestLoadSettingsDropsLegacyKeys(t *testing.T) {
+	s := loadSettingsFile(t, `{"name": "Mina", "keys": {"encourage": "1", "poseMode": "4", "swapGender": "s"}}`)
+	if s.Name != "Mina" {
+		t.Errorf("name = %q, want Mina", s.Name)
+	}
+	if _, ok := s.Keys["poseMode"]; ok {
+		t.Error("poseMode is still in the keys")
+	}
+	if _, ok := s.Keys["swapGender"]; ok {
+		t.Error("swapGender is still in the keys")
+	}
+	if len(s.Keys["encourage"]) != 1 || len(s.Warnings) != 0 {
+		t.Errorf("keys %v, warnings %q", s.Keys, s.Warnings)
+	}
+}
@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"fmt"
+	"sort"
+	"time"
+	"errors"
+	"strconv"
+	"strings"
+	"encoding/csv"
+	"encoding/json"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// LIFETIME STATS
+// ==============================
+
+// LifetimeStats is everything counted since the first launch, as exported by `cliwt stats`
+type LifetimeStats struct {
+	Sessions        int            `json:"sessions"`
+	SecondsOpen     int            `json:"secondsOpen"`
+	MoodSeconds     map[string]int `json:"moodSeconds"` // happy, confused, bored, sad -> seconds
+	LowestHappiness int            `json:"lowestHappiness"`
+	HappinessGiven  int            `json:"happinessGiven"`
+	Encouragements  int            `json:"encouragements"`
+	GiftsGiven      int            `json:"giftsGiven"`
+	GiftsByType     map[string]int `json:"giftsByType"`
+	OutfitsChanged  int            `json:"outfitsChanged"`
+	OutfitsWorn     int            `json:"outfitsWorn"` // different outfits
+	TalkMessages    int            `json:"talkMessages"`
+	GamesPlayed     int            `json:"gamesPlayed"`
+	FocusSessions   int            `json:"focusSessions"`
+	FocusMinutes    int            `json:"focusMinutes"`
+	CheckIns        int            `json:"checkIns"`
+	BestStreak      int            `json:"bestStreak"`
+	Achievements    int            `json:"achievements"` // unlocked
+	Level           int            `json:"level"`
+	XP              int            `json:"xp"`
+	Coins           int            `json:"coins"`
+}
+
+// moodKey names the mood of a happiness value, with the same steps as MoodName
+func moodKey(happiness int) string {
+	switch {
+	case happiness > 800:
+		return "happy"
+	case happiness > 600:
+		return "confused"
+	case happiness > 300:
+		return "bored"
+	default:
+		return "sad"
+	}
+}
+
+// CollectStats gathers the lifetime stats from the save
+func CollectStats() LifetimeStats {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	stats := LifetimeStats{
+		Sessions:        s.Stats["sessions"],
+		SecondsOpen:     s.Stats["secondsOpen"],
+		MoodSeconds:     map[string]int{},
+		LowestHappiness: s.LowestHappiness,
+		HappinessGiven:  s.Stats["happiness"],
+		Encouragements:  s.Stats["encouragements"],
+		GiftsGiven:      s.Stats["gifts"],
+		GiftsByType:     map[string]int{},
+		OutfitsChanged:  s.Stats["outfitsChanged"],
+		OutfitsWorn:     len(s.OutfitsWorn),
+		TalkMessages:    s.Stats["talks"],
+		GamesPlayed:     s.Stats["games"],
+		CheckIns:        s.Stats["checkIns"],
+		BestStreak:      s.BestStreak,
+		Achievements:    len(s.Achievements),
+		Level:           levelFor(s.XP).Level,
+		XP:              s.XP,
+		Coins:           s.Coins,
+	}
+	for mood, seconds := range s.MoodSeconds {
+		stats.MoodSeconds[mood] = seconds
+	}
+	for gift, n := range s.GiftsGiven {
+		stats.GiftsByType[gift] = n
+	}
+	for _, day := range s.FocusStats {
+		stats.FocusSessions += day.Sessions
+		stats.FocusMinutes += day.Minutes
+	}
+	return stats
+}
+
+// countGift counts a given gift by its name
+func countGift(name string) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	if s.GiftsGiven == nil {
+		s.GiftsGiven = map[string]int{}
+	}
+	s.GiftsGiven[name]++
+}
+
+// StartStats counts the session, then the time open, the time at each mood
+// and the lowest happiness on the tick loop
+func StartStats() {
+	TrackStat("sessions", 1)
+
+	last := time.Now()
+	OnTick(func(now time.Time) {
+		elapsed := int(now.Sub(last).Seconds())
+		if elapsed <= 0 {
+			return
+		}
+		last = last.Add(time.Duration(elapsed) * time.Second)
+
+		happinessMutex.Lock()
+		happiness := Happiness
+		happinessMutex.Unlock()
+
+		saveMutex.Lock()
+		defer saveMutex.Unlock()
+		s := currentSave()
+		if s.Stats == nil {
+			s.Stats = map[string]int{}
+		}
+		if s.MoodSeconds == nil {
+			s.MoodSeconds = map[string]int{}
+		}
+		s.Stats["secondsOpen"] += elapsed
+		s.MoodSeconds[moodKey(happiness)] += elapsed
+		s.LowestHappiness = min(s.LowestHappiness, happiness)
+	})
+}
+
+// formatDuration writes seconds as "3h 25m"
+func formatDuration(seconds int) string {
+	d := time.Duration(seconds) * time.Second
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// sortedKeys returns the keys of a counter map, biggest count first
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// ==============================
+// STATS PANEL
+// ==============================
+
+// ShowStats opens the lifetime stats
+func ShowStats(app *tview.Application, pages *tview.Pages, toggle KeyBinding) {
+	if OverlayOpen(pages) {
+		return
+	}
+	stats := CollectStats()
+
+	var b strings.Builder
+	row := func(name string, value any) {
+		fmt.Fprintf(&b, "  %-22s %v\n", name, value)
+	}
+
+	fmt.Fprintf(&b, "[::b]Together[::-]\n")
+	row("Sessions", stats.Sessions)
+	row("Time open", formatDuration(stats.SecondsOpen))
+	row("Daily check-ins", stats.CheckIns)
+	row("Best streak (days)", stats.BestStreak)
+	row("Level", fmt.Sprintf("%d (%d XP)", stats.Level, stats.XP))
+	row("Achievements", stats.Achievements)
+
+	fmt.Fprintf(&b, "\n[::b]Mood[::-]\n")
+	for _, mood := range []string{"happy", "confused", "bored", "sad"} {
+		share := 0
+		if stats.SecondsOpen > 0 {
+			share = stats.MoodSeconds[mood] * 100 / stats.SecondsOpen
+		}
+		row(strings.ToUpper(mood[:1])+mood[1:], fmt.Sprintf("%s %s %d%%",
+			progressBar(share, 100, 10), formatDuration(stats.MoodSeconds[mood]), share))
+	}
+	row("Lowest happiness", fmt.Sprintf("%d%%", stats.LowestHappiness/10))
+	row("Happiness given", stats.HappinessGiven)
+
+	fmt.Fprintf(&b, "\n[::b]Care[::-]\n")
+	row("Encouragements", stats.Encouragements)
+	row("Messages in Talk", stats.TalkMessages)
+	row("Mini-games played", stats.GamesPlayed)
+	row("Focus sessions", fmt.Sprintf("%d (%s)", stats.FocusSessions, formatDuration(stats.FocusMinutes*60)))
+	row("Outfit changes", stats.OutfitsChanged)
+	row("Outfits worn", stats.OutfitsWorn)
+	row("Gifts given", stats.GiftsGiven)
+	for _, gift := range sortedKeys(stats.GiftsByType) {
+		row("  "+tview.Escape(gift), stats.GiftsByType[gift])
+	}
+
+	showTextModal(app, pages, "stats", "| Stats (Esc) |", b.String(), toggle, 60, 24)
+}
+
+// ==============================
+// STATS COMMAND
+// ==============================
+
+const statsUsage = "usage: cliwt [--profile NAME] stats --json | --csv"
+
+// RunStatsCommand handles `cliwt stats ...` for the active profile
+func RunStatsCommand(args []string) error {
+	if len(args) != 1 {
+		return errors.New(statsUsage)
+	}
+	if _, err := LoadSave(); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "--json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(CollectStats())
+	case "--csv":
+		return writeStatsCSV(os.Stdout, CollectStats())
+	}
+	return fmt.Errorf("unknown stats option %q\n%s", args[0], statsUsage)
+}
+
+// writeStatsCSV writes one "stat,value" row per stat; maps become "giftsByType.Plushie" rows
+func writeStatsCSV(out io.Writer, stats LifetimeStats) error {
+	// Reuse the JSON names so both exports agree
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(out)
+	w.Write([]string{"stat", "value"})
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch v := fields[k].(type) {
+		case map[string]any:
+			sub := make([]string, 0, len(v))
+			for name := range v {
+				sub = append(sub, name)
+			}
+			sort.Strings(sub)
+			for _, name := range sub {
+				w.Write([]string{k + "." + name, fmt.Sprint(v[name])})
+			}
+		case float64:
+			w.Write([]string{k, strconv.Itoa(int(v))})
+		default:
+			w.Write([]string{k, fmt.Sprint(v)})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
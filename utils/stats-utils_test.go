@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteStatsCSV(t *testing.T) {
+	stats := LifetimeStats{
+		Sessions:        3,
+		SecondsOpen:     5400,
+		MoodSeconds:     map[string]int{"sad": 60, "happy": 4000},
+		LowestHappiness: 120,
+		GiftsGiven:      3,
+		GiftsByType:     map[string]int{"Plushie": 2, "Tea, hot": 1},
+		XP:              1234567, // not printed as 1.234567e+06
+		Coins:           42,
+	}
+	var out strings.Builder
+	if err := writeStatsCSV(&out, stats); err != nil {
+		t.Fatalf("writeStatsCSV: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"stat,value",
+		"achievements,0",
+		"bestStreak,0",
+		"checkIns,0",
+		"coins,42",
+		"encouragements,0",
+		"focusMinutes,0",
+		"focusSessions,0",
+		"gamesPlayed,0",
+		"giftsByType.Plushie,2",
+		`"giftsByType.Tea, hot",1`,
+		"giftsGiven,3",
+		"happinessGiven,0",
+		"level,0",
+		"lowestHappiness,120",
+		"moodSeconds.happy,4000",
+		"moodSeconds.sad,60",
+		"outfitsChanged,0",
+		"outfitsWorn,0",
+		"secondsOpen,5400",
+		"sessions,3",
+		"talkMessages,0",
+		"xp,1234567",
+	}, "\n") + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("writeStatsCSV wrote\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// DAILY STREAK
+// ==============================
+
+// streakMilestone is the reward for a number of daily check-ins in a row
+type streakMilestone struct {
+	Days   int
+	Coins  int
+	Outfit string // exclusive outfit unlocked, relative to clothes/
+	Line   string
+}
+
+var streakMilestones = []streakMilestone{
+	{Days: 3, Coins: 15, Line: "Three days in a row! I'm starting to count on you ♥"},
+	{Days: 7, Coins: 30, Outfit: "exclusive/sakura-hoodie", Line: "A whole week together! I got a new hoodie to celebrate ♥"},
+	{Days: 14, Coins: 50, Line: "Two weeks! You really come back every single day..."},
+	{Days: 30, Coins: 100, Outfit: "exclusive/midnight-coat", Line: "One month, {user}! I saved something special for today ♥"},
+	{Days: 100, Coins: 300, Line: "100 days... I don't know what I'd do without you."},
+}
+
+// coins given for every daily check-in
+const checkInCoins = 5
+
+// checkIn is the outcome of a daily check-in
+type checkIn struct {
+	Streak    int
+	Lost      int // streak that was broken, 0 if none
+	DaysAway  int // days since she was last seen, when the streak was broken
+	Milestone *streakMilestone
+}
+
+// Streak returns the current number of days in a row
+func Streak() int {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	return currentSave().Streak
+}
+
+// dayNumber counts calendar days, so DST changes don't shift dates
+func dayNumber(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// recordCheckIn records today's check-in; returns false if it was already done
+func recordCheckIn(now time.Time) (checkIn, bool) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	today := now.Format("2006-01-02")
+	if s.LastCheckIn == today && s.Streak > 0 {
+		return checkIn{Streak: s.Streak}, false
+	}
+
+	var result checkIn
+	last, err := time.ParseInLocation("2006-01-02", s.LastCheckIn, now.Location())
+	switch {
+	case err != nil || s.Streak == 0:
+		s.Streak = 1
+	case dayNumber(now)-dayNumber(last) == 1:
+		s.Streak++
+	default:
+		result.Lost = s.Streak
+		seen := last
+		if s.LastSeen.After(seen) {
+			seen = s.LastSeen
+		}
+		result.DaysAway = dayNumber(now) - dayNumber(seen)
+		s.Streak = 1
+	}
+	s.LastCheckIn = today
+	s.BestStreak = max(s.BestStreak, s.Streak)
+	result.Streak = s.Streak
+
+	for i := range streakMilestones {
+		if streakMilestones[i].Days == s.Streak {
+			result.Milestone = &streakMilestones[i]
+		}
+	}
+	return result, true
+}
+
+// StartStreak checks in for today and again whenever the day changes while the app is open.
+// Check-ins give coins, milestones give coins, exclusive outfits and special lines;
+// a broken streak makes her sad.
+func StartStreak(
+	waifuArt, chatBox *tview.TextView,
+	head *string,
+	happyHead, waifuName string,
+	currentBody *string,
+) {
+	// react shows the result; called on the UI goroutine (or before the app runs)
+	react := func(c checkIn) {
+		SetStatusItem("streak", fmt.Sprintf("🔥%d", c.Streak))
+		AddCoins(checkInCoins)
+
+		face := happyHead
+		var line string
+		switch {
+		case c.Lost > 1:
+			face = sad
+			line = fmt.Sprintf("You were gone for %d days... our %d day streak is over. Let's start again? (+%d¢)",
+				c.DaysAway, c.Lost, checkInCoins)
+		case c.Streak == 1:
+			line = fmt.Sprintf("First check-in! Come back tomorrow and we'll start a streak ♥ (+%d¢)", checkInCoins)
+		default:
+			line = fmt.Sprintf("Day %d in a row! Welcome back ♥ (+%d¢)", c.Streak, checkInCoins)
+		}
+
+		if m := c.Milestone; m != nil {
+			var rewards []string
+			if m.Coins > 0 {
+				AddCoins(m.Coins)
+				rewards = append(rewards, fmt.Sprintf("+%d¢", m.Coins))
+			}
+			if m.Outfit != "" && UnlockOutfit(m.Outfit) {
+				rewards = append(rewards, "new outfit: "+m.Outfit)
+			}
+			line = RenderTemplate(m.Line, CurrentContext())
+			if len(rewards) > 0 {
+				line += " (" + strings.Join(rewards, ", ") + ")"
+			}
+		}
+
+		SetChat(chatBox, waifuName+": "+line)
+		waifuArt.SetText(face + "\n" + *currentBody)
+		time.AfterFunc(3*time.Second, func() {
+			UIEventsChan <- func() {
+				waifuArt.SetText(*head + "\n" + *currentBody)
+			}
+		})
+	}
+
+	if c, ok := recordCheckIn(time.Now()); ok {
+		react(c)
+		TrackStat("checkIns", 1)
+	} else {
+		SetStatusItem("streak", fmt.Sprintf("🔥%d", c.Streak))
+	}
+
+	OnTick(func(now time.Time) {
+		if c, ok := recordCheckIn(now); ok {
+			UIEventsChan <- func() {
+				react(c)
+			}
+			TrackStat("checkIns", 1)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"time"
+	"testing"
+)
+
+func TestRecordCheckIn(t *testing.T) {
+	previous := cachedSave
+	t.Cleanup(func() { cachedSave = previous })
+
+	now := time.Date(2026, time.March, 10, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		save      SaveState
+		want      checkIn
+		recorded  bool
+		milestone int // days of the milestone reached, 0 if none
+		best      int
+	}{
+		{"first check-in", SaveState{}, checkIn{Streak: 1}, true, 0, 1},
+		{"already today", SaveState{LastCheckIn: "2026-03-10", Streak: 4, BestStreak: 4},
+			checkIn{Streak: 4}, false, 0, 4},
+		{"next day", SaveState{LastCheckIn: "2026-03-09", Streak: 2, BestStreak: 6},
+			checkIn{Streak: 3}, true, 3, 6},
+		{"new best", SaveState{LastCheckIn: "2026-03-09", Streak: 9, BestStreak: 9},
+			checkIn{Streak: 10}, true, 0, 10},
+		{"broken streak", SaveState{LastCheckIn: "2026-03-05", Streak: 5, BestStreak: 5},
+			checkIn{Streak: 1, Lost: 5, DaysAway: 5}, true, 0, 5},
+		{"seen after the last check-in", SaveState{
+			LastCheckIn: "2026-03-05", Streak: 5, BestStreak: 5,
+			LastSeen: time.Date(2026, time.March, 8, 23, 0, 0, 0, time.UTC),
+		}, checkIn{Streak: 1, Lost: 5, DaysAway: 2}, true, 0, 5},
+		{"unreadable date", SaveState{LastCheckIn: "yesterday", Streak: 5, BestStreak: 5},
+			checkIn{Streak: 1}, true, 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			save := tt.save
+			cachedSave = &save
+
+			got, recorded := recordCheckIn(now)
+			milestone := 0
+			if got.Milestone != nil {
+				milestone = got.Milestone.Days
+			}
+			got.Milestone = nil
+			if got != tt.want || recorded != tt.recorded || milestone != tt.milestone {
+				t.Errorf("recordCheckIn = %+v, %v, milestone %d, want %+v, %v, milestone %d",
+					got, recorded, milestone, tt.want, tt.recorded, tt.milestone)
+			}
+			if save.LastCheckIn != "2026-03-10" || save.Streak != tt.want.Streak || save.BestStreak != tt.best {
+				t.Errorf("saved %q, streak %d, best %d", save.LastCheckIn, save.Streak, save.BestStreak)
+			}
+		})
+	}
+}
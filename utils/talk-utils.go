@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	"context"
+	"strings"
+	"math/rand"
+
+	"github.com/rivo/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ==============================
+// TALK
+// ==============================
+
+// DialogueReply is what she answers to a line, with its effects
+type DialogueReply struct {
+	Line       string
+	Happiness  int
+	Expression string // file in expressions/, empty for the default face
+}
+
+// Answer picks the reply of the first matching rule, or a fallback line
+func (d *DialogueFile) Answer(text string, ctx TemplateContext) DialogueReply {
+	for i := range d.Rules {
+		r := &d.Rules[i]
+		groups, ok := r.Match(text, ctx)
+		if !ok {
+			continue
+		}
+		line := r.Responses[rand.Intn(len(r.Responses))]
+		// "{1}", "{2}"... are the groups of the pattern
+		for n := len(groups) - 1; n >= 0; n-- {
+			line = strings.ReplaceAll(line, fmt.Sprintf("{%d}", n), groups[n])
+		}
+		return DialogueReply{
+			Line:       RenderTemplate(line, ctx),
+			Happiness:  r.Happiness,
+			Expression: r.Expression,
+		}
+	}
+	return DialogueReply{Line: RenderTemplate(d.Fallback[rand.Intn(len(d.Fallback))], ctx)}
+}
+
+// How many previous messages of the conversation the chat bot gets
+const chatMemory = 12
+
+// Talk swaps the Action Space for an input; every line you send gets an answer,
+// from the chat bot if one is configured, else from dialogue.json.
+// The face of the answer stays for `duration`, then `head` comes back.
+func Talk(
+	app *tview.Application,
+	grid *tview.Grid,
+	actionSpace *tview.List,
+	waifuArt, chatBox *tview.TextView,
+	head *string,
+	happyHead, waifuName string,
+	currentBody *string,
+	duration time.Duration,
+) {
+	dialogue, err := LoadDialogue()
+	if err != nil {
+		SetChat(chatBox, err.Error())
+		return
+	}
+
+	p := cachedPaletteOrDefault()
+	input := tview.NewInputField().
+		SetLabel("> ").
+		SetFieldBackgroundColor(tcell.GetColor(p.Background)).
+		SetFieldTextColor(tcell.GetColor(p.Foreground)).
+		SetLabelColor(tcell.GetColor(p.Accent))
+	input.SetBorder(true).
+		SetTitle("| Talk (Esc to stop) |").
+		SetBorderColor(tcell.GetColor(p.Border)).
+		SetTitleColor(tcell.GetColor(p.Title)).
+		SetBackgroundColor(tcell.GetColor(p.Background))
+
+	// What was said since Talk was opened
+	transcript := tview.NewTextView().SetWrap(true).SetDynamicColors(true)
+	ApplyTextViewPalette(p, transcript)
+	transcript.SetTitle("| Conversation |")
+
+	box := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 3, 0, true).
+		AddItem(transcript, 0, 1, false)
+
+	// The face of the answer stays for `duration`, and its happiness is applied
+	var restore *time.Timer
+	react := func(reply DialogueReply) {
+		face := *head
+		switch {
+		case reply.Happiness > 0:
+			face = happyHead
+			IncreaseHappiness(reply.Happiness)
+		case reply.Happiness < 0:
+			face = sad
+			DecreaseHappiness(-reply.Happiness)
+		}
+		if reply.Expression != "" {
+			face = LoadOptionalASCII(BasePath+"/expressions/"+reply.Expression, face)
+		}
+		waifuArt.SetText(face + "\n" + *currentBody)
+
+		// A new answer pushes the return to the normal face back
+		if restore != nil {
+			restore.Stop()
+		}
+		restore = time.AfterFunc(duration, func() {
+			if UIEventsChan != nil {
+				UIEventsChan <- func() {
+					waifuArt.SetText(*head + "\n" + *currentBody)
+				}
+			}
+		})
+	}
+	addToTranscript := func(user, text, answer string) {
+		fmt.Fprintf(transcript, "[::b]%s:[::-] %s\n%s: %s\n",
+			tview.Escape(user), tview.Escape(text), tview.Escape(waifuName), tview.Escape(answer))
+		transcript.ScrollToEnd()
+	}
+
+	// With a chat bot the words come from the model, the effects still come from the rules
+	var provider ChatProvider
+	timeout := time.Minute
+	if settings, err := LoadSettings(); err == nil {
+		provider = NewChatProvider(settings)
+		if settings.ChatBot.Timeout > 0 {
+			timeout = time.Duration(settings.ChatBot.Timeout) * time.Second
+		}
+	}
+	var (
+		turns      []ChatTurn // conversation so far, sent back to the model
+		busy       bool
+		closed     bool
+		cancel     context.CancelFunc
+		warnedDown bool
+	)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			closed = true
+			if cancel != nil {
+				cancel()
+			}
+			LockGridChanges = false
+			grid.RemoveItem(box)
+			grid.AddItem(actionSpace, 0, 0, 1, 1, 0, 0, true)
+			app.SetFocus(actionSpace)
+			return
+		}
+		text := strings.TrimSpace(input.GetText())
+		if key != tcell.KeyEnter || text == "" || busy {
+			return
+		}
+		input.SetText("")
+		TrackStat("talks", 1)
+
+		ctx := CurrentContext()
+		reply := dialogue.Answer(text, ctx)
+		LogChat(ctx.User + ": " + text)
+
+		if provider == nil || UIEventsChan == nil {
+			SetChat(chatBox, waifuName+": "+reply.Line)
+			addToTranscript(ctx.User, text, reply.Line)
+			react(reply)
+			return
+		}
+
+		busy = true
+		ShowChat(chatBox, waifuName+": ...")
+		turns = append(turns, ChatTurn{Role: "user", Content: text})
+		messages := append([]ChatTurn{{Role: "system", Content: PersonaPrompt(ctx)}},
+			turns[max(len(turns)-chatMemory, 0):]...)
+
+		var requestCtx context.Context
+		requestCtx, cancel = context.WithTimeout(context.Background(), timeout)
+		done := cancel
+
+		go func() {
+			var answer strings.Builder
+			err := provider.Reply(requestCtx, messages, func(token string) {
+				answer.WriteString(token)
+				partial := answer.String()
+				UIEventsChan <- func() {
+					if !closed {
+						ShowChat(chatBox, waifuName+": "+strings.TrimSpace(partial))
+					}
+				}
+			})
+			done()
+			line := strings.TrimSpace(answer.String())
+
+			UIEventsChan <- func() {
+				busy = false
+				if closed {
+					return
+				}
+				if line == "" {
+					// Endpoint down (or silent): fall back to the rule-based line
+					if err != nil && !warnedDown {
+						warnedDown = true
+						fmt.Fprintf(transcript, "[::d]%s[::-]\n", tview.Escape("(chat bot unavailable, using dialogue.json)"))
+					}
+					line = reply.Line
+					SetChat(chatBox, waifuName+": "+line)
+				} else {
+					// Already shown as it streamed in
+					ShowChat(chatBox, waifuName+": "+line)
+					LogChat(waifuName + ": " + line)
+				}
+				turns = append(turns, ChatTurn{Role: "assistant", Content: line})
+				addToTranscript(ctx.User, text, line)
+				react(reply)
+			}
+		}()
+	})
+
+	// No other menu may take the grid while she listens
+	LockGridChanges = true
+	grid.RemoveItem(actionSpace)
+	grid.AddItem(box, 0, 0, 1, 1, 0, 0, true)
+	app.SetFocus(input)
+}
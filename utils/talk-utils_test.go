@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDialogueAnswer(t *testing.T) {
+	d := &DialogueFile{
+		Rules: []DialogueRule{
+			{Keywords: []string{"hello"}, When: "sad", Responses: []string{"...hi."}},
+			{Keywords: []string{"hello"}, Responses: []string{"Hi {user}!"}, Happiness: 2, Expression: "-happy"},
+			{Pattern: `(?i)\bi('m| am) (sad|tired)\b`, Responses: []string{"You're {2}?"}},
+		},
+		Fallback: []string{"I'm listening, {user}."},
+	}
+	for i := range d.Rules {
+		r := &d.Rules[i]
+		if r.Pattern != "" {
+			r.pattern = regexp.MustCompile(r.Pattern)
+		}
+		conditions, unknown := ParseConditions(strings.Fields(r.When))
+		if len(unknown) > 0 {
+			t.Fatalf("unknown tags %v", unknown)
+		}
+		r.conditions = conditions
+	}
+
+	happy := TemplateContext{User: "Alex", Happiness: 1000}
+	sad := TemplateContext{User: "Alex", Happiness: 100}
+	tests := []struct {
+		text string
+		ctx  TemplateContext
+		want DialogueReply
+	}{
+		{"Hello there", happy, DialogueReply{Line: "Hi Alex!", Happiness: 2, Expression: "-happy"}},
+		{"hello", sad, DialogueReply{Line: "...hi."}},
+		{"I am TIRED today", happy, DialogueReply{Line: "You're TIRED?"}},
+		{"othello", happy, DialogueReply{Line: "I'm listening, Alex."}},
+		{"what's up", happy, DialogueReply{Line: "I'm listening, Alex."}},
+	}
+	for _, tt := range tests {
+		if got := d.Answer(tt.text, tt.ctx); got != tt.want {
+			t.Errorf("Answer(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"os"
+	"fmt"
+	"path"
+	"time"
+	"strconv"
+	"strings"
+)
+
+// ==============================
+// TEMPLATE CONTEXT
+// ==============================
+
+// TemplateContext is what lines can talk about: {user}, {name}, {time}, ...
+type TemplateContext struct {
+	Now       time.Time
+	Happiness int // 0-1000
+	User      string
+	Name      string
+	Outfit    string
+	Level     int // relationship level
+}
+
+// CurrentContext snapshots the state for templating and conditions
+func CurrentContext() TemplateContext {
+	happinessMutex.Lock()
+	happiness := Happiness
+	happinessMutex.Unlock()
+
+	ctx := TemplateContext{
+		Now:       time.Now(),
+		Happiness: happiness,
+		User:      userName(),
+		Outfit:    outfitLabel(wornOutfit()),
+		Level:     CurrentLevel().Level,
+	}
+	if s, err := LoadSettings(); err == nil {
+		ctx.Name = s.Name
+	}
+	return ctx
+}
+
+// outfitLabel is the name of an outfit without its clothes/ subdirectory ("exclusive/sakura-hoodie" -> "sakura-hoodie")
+func outfitLabel(name string) string {
+	if name == "" {
+		return ""
+	}
+	return path.Base(name)
+}
+
+// userName is the name she calls you: settings "userName", then $USER
+func userName() string {
+	if s, err := LoadSettings(); err == nil && s.UserName != "" {
+		return s.UserName
+	}
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return "you"
+}
+
+// RenderTemplate replaces the {variables} of a line
+func RenderTemplate(text string, ctx TemplateContext) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	return strings.NewReplacer(
+		"{user}", ctx.User,
+		"{name}", ctx.Name,
+		"{time}", ctx.Now.Format("15:04"),
+		"{day}", ctx.Now.Weekday().String(),
+		"{happiness}", fmt.Sprintf("%d%%", ctx.Happiness/10),
+		"{outfit}", ctx.Outfit,
+		"{level}", strconv.Itoa(ctx.Level),
+	).Replace(text)
+}
+
+// ==============================
+// CONDITIONS
+// ==============================
+
+// TimeOfDay returns "morning" (5-12), "afternoon" (12-17), "evening" (17-22) or "night"
+func TimeOfDay(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 12:
+		return "morning"
+	case h >= 12 && h < 17:
+		return "afternoon"
+	case h >= 17 && h < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+var weekdayTags = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// Conditions restrict when a line may be used. Tags of the same kind are
+// alternatives ("sat sun"), different kinds must all match ("morning mon").
+// The zero value always matches.
+type Conditions struct {
+	Times    []string
+	Days     []time.Weekday
+	MoodMin  *int // inclusive
+	MoodMax  *int // inclusive
+	LevelMin *int // inclusive
+	LevelMax *int // inclusive
+}
+
+// ParseConditions reads tags like "morning", "weekend", "mon", "happy", "sad",
+// "mood>700", "mood<=300", "mood=200-600" or "level>=3". Unknown tags are returned for the caller.
+func ParseConditions(tags []string) (Conditions, []string) {
+	var c Conditions
+	var rest []string
+
+	for _, tag := range tags {
+		t := strings.ToLower(tag)
+		switch {
+		case t == "morning" || t == "afternoon" || t == "evening" || t == "night":
+			c.Times = append(c.Times, t)
+		case t == "day": // the name of the phase in calendar.json
+			c.Times = append(c.Times, "afternoon")
+		case t == "weekend":
+			c.Days = append(c.Days, time.Saturday, time.Sunday)
+		case t == "weekday":
+			c.Days = append(c.Days, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+		case t == "happy":
+			c.MoodMin = intPtr(701)
+		case t == "sad":
+			c.MoodMax = intPtr(300)
+		case strings.HasPrefix(t, "mood"):
+			if !parseRange(t[len("mood"):], &c.MoodMin, &c.MoodMax) {
+				rest = append(rest, tag)
+			}
+		case strings.HasPrefix(t, "level"):
+			if !parseRange(t[len("level"):], &c.LevelMin, &c.LevelMax) {
+				rest = append(rest, tag)
+			}
+		default:
+			if d, ok := weekdayTags[t]; ok {
+				c.Days = append(c.Days, d)
+			} else {
+				rest = append(rest, tag)
+			}
+		}
+	}
+	return c, rest
+}
+
+// parseRange handles ">700", ">=700", "<300", "<=300" and "=200-600" into inclusive bounds
+func parseRange(expr string, lo, hi **int) bool {
+	ops := []string{">=", "<=", ">", "<", "="}
+	for _, op := range ops {
+		if !strings.HasPrefix(expr, op) {
+			continue
+		}
+		value := expr[len(op):]
+		if op == "=" && strings.Contains(value, "-") {
+			from, to, _ := strings.Cut(value, "-")
+			a, err1 := strconv.Atoi(from)
+			b, err2 := strconv.Atoi(to)
+			if err1 != nil || err2 != nil {
+				return false
+			}
+			*lo, *hi = intPtr(a), intPtr(b)
+			return true
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return false
+		}
+		switch op {
+		case ">=":
+			*lo = intPtr(n)
+		case ">":
+			*lo = intPtr(n + 1)
+		case "<=":
+			*hi = intPtr(n)
+		case "<":
+			*hi = intPtr(n - 1)
+		case "=":
+			*lo, *hi = intPtr(n), intPtr(n)
+		}
+		return true
+	}
+	return false
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// IsEmpty reports whether the conditions always match
+func (c Conditions) IsEmpty() bool {
+	return len(c.Times) == 0 && len(c.Days) == 0 && c.MoodMin == nil && c.MoodMax == nil &&
+		c.LevelMin == nil && c.LevelMax == nil
+}
+
+// Match reports whether the conditions hold in the context
+func (c Conditions) Match(ctx TemplateContext) bool {
+	if len(c.Times) > 0 {
+		now, ok := TimeOfDay(ctx.Now), false
+		for _, t := range c.Times {
+			ok = ok || t == now
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(c.Days) > 0 {
+		ok := false
+		for _, d := range c.Days {
+			ok = ok || d == ctx.Now.Weekday()
+		}
+		if !ok {
+			return false
+		}
+	}
+	if c.MoodMin != nil && ctx.Happiness < *c.MoodMin {
+		return false
+	}
+	if c.MoodMax != nil && ctx.Happiness > *c.MoodMax {
+		return false
+	}
+	if c.LevelMin != nil && ctx.Level < *c.LevelMin {
+		return false
+	}
+	if c.LevelMax != nil && ctx.Level > *c.LevelMax {
+		return false
+	}
+	return true
+}
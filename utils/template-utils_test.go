@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"time"
+	"reflect"
+	"testing"
+)
+
+// bound prints an optional bound for error messages
+func bound(p *int) any {
+	if p == nil {
+		return "none"
+	}
+	return *p
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		expr   string
+		ok     bool
+		lo, hi *int
+	}{
+		{">700", true, intPtr(701), nil},
+		{">=700", true, intPtr(700), nil},
+		{"<300", true, nil, intPtr(299)},
+		{"<=300", true, nil, intPtr(300)},
+		{"=5", true, intPtr(5), intPtr(5)},
+		{"=200-600", true, intPtr(200), intPtr(600)},
+		{"=-5", false, nil, nil},
+		{"=200-", false, nil, nil},
+		{">high", false, nil, nil},
+		{"700", false, nil, nil},
+		{"", false, nil, nil},
+	}
+	for _, tt := range tests {
+		var lo, hi *int
+		ok := parseRange(tt.expr, &lo, &hi)
+		if ok != tt.ok || !reflect.DeepEqual(lo, tt.lo) || !reflect.DeepEqual(hi, tt.hi) {
+			t.Errorf("parseRange(%q) = %v [%v, %v], want %v [%v, %v]",
+				tt.expr, ok, bound(lo), bound(hi), tt.ok, bound(tt.lo), bound(tt.hi))
+		}
+	}
+}
+
+func TestParseConditions(t *testing.T) {
+	tests := []struct {
+		tags    []string
+		want    Conditions
+		unknown []string
+	}{
+		{nil, Conditions{}, nil},
+		{[]string{"Morning", "day"}, Conditions{Times: []string{"morning", "afternoon"}}, nil},
+		{[]string{"weekend", "mon"}, Conditions{Days: []time.Weekday{time.Saturday, time.Sunday, time.Monday}}, nil},
+		{[]string{"happy"}, Conditions{MoodMin: intPtr(701)}, nil},
+		{[]string{"sad", "level>=3"}, Conditions{MoodMax: intPtr(300), LevelMin: intPtr(3)}, nil},
+		{[]string{"mood=200-600"}, Conditions{MoodMin: intPtr(200), MoodMax: intPtr(600)}, nil},
+		{[]string{"evening", "weight=3", "mood>lots", "brunch"},
+			Conditions{Times: []string{"evening"}}, []string{"weight=3", "mood>lots", "brunch"}},
+	}
+	for _, tt := range tests {
+		got, unknown := ParseConditions(tt.tags)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseConditions(%q) = %+v, want %+v", tt.tags, got, tt.want)
+		}
+		if !reflect.DeepEqual(unknown, tt.unknown) {
+			t.Errorf("ParseConditions(%q) left %q, want %q", tt.tags, unknown, tt.unknown)
+		}
+	}
+}
+
+func TestOutfitLabel(t *testing.T) {
+	for name, want := range map[string]string{
+		"":                        "",
+		"hoodie":                  "hoodie",
+		"exclusive/sakura-hoodie": "sakura-hoodie",
+	} {
+		if got := outfitLabel(name); got != want {
+			t.Errorf("outfitLabel(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
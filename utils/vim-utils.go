@@ -0,0 +1,310 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ==============================
+// VIM NAVIGATION
+// ==============================
+
+// Vim motions, in the order they are matched
+const (
+	vimDown       = "down"
+	vimUp         = "up"
+	vimSelect     = "select"
+	vimBack       = "back"
+	vimTop        = "top"
+	vimBottom     = "bottom"
+	vimSearch     = "search"
+	vimSearchNext = "searchNext"
+	vimSearchPrev = "searchPrev"
+)
+
+type vimMotion struct {
+	name string
+	keys []KeySpec
+}
+
+// VimNavigator is the modal navigation layer for lists: counts ("3j"),
+// sequences ("gg"), jumps ("G") and search ("/", "n", "N").
+type VimNavigator struct {
+	app     *tview.Application
+	pages   *tview.Pages
+	motions []vimMotion
+	timeout time.Duration
+	replay  func(event *tcell.EventKey)
+
+	count      int
+	pending    []*tcell.EventKey // keys of an unfinished sequence (count included)
+	generation int               // invalidates stale timeouts
+	lastSearch string
+}
+
+// NewVimNavigator parses the vim keys of the settings.
+// `replay` receives a lone key that timed out so it can still trigger its action.
+func NewVimNavigator(app *tview.Application, pages *tview.Pages, settings *Settings,
+	replay func(event *tcell.EventKey)) (*VimNavigator, error) {
+
+	k := settings.VimKeys
+	specs := []struct{ name, seq string }{
+		{vimDown, k.Down}, {vimUp, k.Up}, {vimSelect, k.Select}, {vimBack, k.Back},
+		{vimTop, k.Top}, {vimBottom, k.Bottom},
+		{vimSearch, k.Search}, {vimSearchNext, k.SearchNext}, {vimSearchPrev, k.SearchPrev},
+	}
+
+	v := &VimNavigator{
+		app:     app,
+		pages:   pages,
+		timeout: time.Duration(settings.VimTimeout) * time.Millisecond,
+		replay:  replay,
+	}
+	if v.timeout <= 0 {
+		v.timeout = 800 * time.Millisecond
+	}
+
+	for _, s := range specs {
+		// An empty key disables the motion
+		if s.seq == "" {
+			continue
+		}
+		keys, err := ParseKeySequence(s.seq)
+		if err != nil {
+			return nil, fmt.Errorf("vimKeys.%s: %w", s.name, err)
+		}
+		v.motions = append(v.motions, vimMotion{s.name, keys})
+	}
+	return v, nil
+}
+
+// HandleKey feeds a key to the layer while `list` is focused.
+// Returns handled=false when the key should go on to the action shortcuts.
+func (v *VimNavigator) HandleKey(list *tview.List, event *tcell.EventKey) (*tcell.EventKey, bool) {
+	// Counts: digits before a motion ("0" only continues a count)
+	if len(v.pending) == v.countDigits() && event.Key() == tcell.KeyRune &&
+		event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) == 0 &&
+		event.Rune() >= '0' && event.Rune() <= '9' && (event.Rune() != '0' || v.count > 0) {
+
+		v.count = v.count*10 + int(event.Rune()-'0')
+		v.pending = append(v.pending, event)
+		v.startTimeout()
+		return nil, true
+	}
+
+	seq := append(v.pending[v.countDigits():], event)
+	partial := false
+	for _, m := range v.motions {
+		switch matchSequence(m.keys, seq) {
+		case sequenceFull:
+			count := v.count
+			v.reset()
+			return v.run(list, m.name, count), true
+		case sequencePartial:
+			partial = true
+		}
+	}
+	if partial {
+		v.pending = append(v.pending, event)
+		v.startTimeout()
+		return nil, true
+	}
+
+	// Not a motion: drop the unfinished sequence and let the key through
+	v.reset()
+	return event, false
+}
+
+// countDigits returns how many pending keys belong to the count
+func (v *VimNavigator) countDigits() int {
+	if v.count == 0 {
+		return 0
+	}
+	return len(fmt.Sprint(v.count))
+}
+
+func (v *VimNavigator) reset() {
+	v.count = 0
+	v.pending = nil
+	v.generation++
+}
+
+// startTimeout gives up on the pending keys after the timeout, like vim's timeoutlen.
+// A single pending key is replayed so e.g. "1" still triggers its action.
+func (v *VimNavigator) startTimeout() {
+	v.generation++
+	gen := v.generation
+	time.AfterFunc(v.timeout, func() {
+		if UIEventsChan == nil {
+			return
+		}
+		UIEventsChan <- func() {
+			if gen != v.generation {
+				return
+			}
+			pending := v.pending
+			v.reset()
+			if len(pending) == 1 && v.replay != nil {
+				v.replay(pending[0])
+			}
+		}
+	})
+}
+
+type sequenceMatch int
+
+const (
+	sequenceNone sequenceMatch = iota
+	sequencePartial
+	sequenceFull
+)
+
+func matchSequence(keys []KeySpec, pressed []*tcell.EventKey) sequenceMatch {
+	if len(pressed) > len(keys) {
+		return sequenceNone
+	}
+	for i, ev := range pressed {
+		if !keys[i].Matches(ev) {
+			return sequenceNone
+		}
+	}
+	if len(pressed) == len(keys) {
+		return sequenceFull
+	}
+	return sequencePartial
+}
+
+// run applies a motion to the list
+func (v *VimNavigator) run(list *tview.List, motion string, count int) *tcell.EventKey {
+	n := list.GetItemCount()
+	if n == 0 {
+		return nil
+	}
+	steps := count
+	if steps == 0 {
+		steps = 1
+	}
+	current := list.GetCurrentItem()
+
+	switch motion {
+	case vimDown:
+		list.SetCurrentItem(min(current+steps, n-1))
+	case vimUp:
+		list.SetCurrentItem(max(current-steps, 0))
+	case vimTop:
+		// "5gg" jumps to the 5th item, like a line number
+		list.SetCurrentItem(max(min(count, n), 1) - 1)
+	case vimBottom:
+		if count > 0 {
+			list.SetCurrentItem(min(count, n) - 1)
+		} else {
+			list.SetCurrentItem(n - 1)
+		}
+	case vimSelect:
+		// Simulate Enter key to trigger the selected function
+		return tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	case vimBack:
+		// Go back/escape
+		return tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
+	case vimSearch:
+		v.openSearch(list)
+	case vimSearchNext:
+		for i := 0; i < steps; i++ {
+			v.jumpToMatch(list, v.lastSearch, 1, true)
+		}
+	case vimSearchPrev:
+		for i := 0; i < steps; i++ {
+			v.jumpToMatch(list, v.lastSearch, -1, true)
+		}
+	}
+	return nil
+}
+
+// ==============================
+// SEARCH
+// ==============================
+
+// openSearch shows a one line input; matches are selected while typing
+func (v *VimNavigator) openSearch(list *tview.List) {
+	p := cachedPaletteOrDefault()
+	start := list.GetCurrentItem()
+
+	input := tview.NewInputField().
+		SetLabel("/").
+		SetFieldBackgroundColor(tcell.GetColor(p.Background)).
+		SetFieldTextColor(tcell.GetColor(p.Foreground)).
+		SetLabelColor(tcell.GetColor(p.Accent))
+	input.SetBorder(true).
+		SetTitle("| Search |").
+		SetBorderColor(tcell.GetColor(p.Border)).
+		SetTitleColor(tcell.GetColor(p.Title)).
+		SetBackgroundColor(tcell.GetColor(p.Background))
+
+	input.SetChangedFunc(func(text string) {
+		list.SetCurrentItem(start)
+		v.jumpToMatch(list, text, 1, false)
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			v.lastSearch = input.GetText()
+		} else {
+			list.SetCurrentItem(start)
+		}
+		closeModal(v.app, v.pages, "search")
+	})
+
+	showModal(v.app, v.pages, "search", input, input, 40, 3)
+}
+
+// jumpToMatch selects the next item (in `dir`) whose text contains the query.
+// With skipCurrent=false the current item itself may match.
+func (v *VimNavigator) jumpToMatch(list *tview.List, query string, dir int, skipCurrent bool) {
+	n := list.GetItemCount()
+	if query == "" || n == 0 {
+		return
+	}
+	query = strings.ToLower(query)
+
+	current := list.GetCurrentItem()
+	for i := 0; i < n; i++ {
+		offset := i
+		if skipCurrent {
+			offset = i + 1
+		}
+		idx := ((current+dir*offset)%n + n) % n
+		main, _ := list.GetItemText(idx)
+		if strings.Contains(strings.ToLower(main), query) {
+			list.SetCurrentItem(idx)
+			return
+		}
+	}
+}
+
+// ==============================
+// CONFLICTS
+// ==============================
+
+// VimConflicts lists action keys that the vim layer shadows while a list is focused
+func VimConflicts(settings *Settings, actions []*Action) []string {
+	k := settings.VimKeys
+	var conflicts []string
+	for _, seq := range []string{k.Down, k.Up, k.Select, k.Back, k.Top, k.Bottom, k.Search, k.SearchNext, k.SearchPrev} {
+		keys, err := ParseKeySequence(seq)
+		if err != nil || len(keys) == 0 {
+			continue
+		}
+		first := keys[0]
+		for _, a := range actions {
+			for _, spec := range settings.Keys.For(a) {
+				if k, err := ParseKeySpec(spec); err == nil && k == first {
+					conflicts = append(conflicts, fmt.Sprintf("%s (%s)", k, a.Label))
+				}
+			}
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,306 @@
+package utils
+
+import (
+	"path"
+	"sort"
+	"slices"
+
+	"github.com/rivo/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ==============================
+// WARDROBE STATE
+// ==============================
+
+// Sort orders of the wardrobe, cycled with "s"
+var wardrobeSorts = []string{"name", "worn"}
+
+var wardrobeSortLabels = map[string]string{
+	"name": "A-Z",
+	"worn": "Worn",
+}
+
+// rememberOutfit keeps the outfit to put back on at the next launch
+func rememberOutfit(name string) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	currentSave().LastOutfit = name
+}
+
+// countOutfit counts how many times an outfit was put on
+func countOutfit(name string) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	if s.OutfitWears == nil {
+		s.OutfitWears = map[string]int{}
+	}
+	s.OutfitWears[name]++
+}
+
+// toggleFavoriteOutfit adds or removes an outfit from the favorites
+func toggleFavoriteOutfit(name string) {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	s := currentSave()
+	if i := slices.Index(s.FavoriteOutfits, name); i >= 0 {
+		s.FavoriteOutfits = slices.Delete(s.FavoriteOutfits, i, i+1)
+		return
+	}
+	s.FavoriteOutfits = append(s.FavoriteOutfits, name)
+}
+
+// wardrobeSort returns the saved sort order, "name" if unset
+func wardrobeSort() string {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	if sortBy := currentSave().WardrobeSort; slices.Contains(wardrobeSorts, sortBy) {
+		return sortBy
+	}
+	return wardrobeSorts[0]
+}
+
+// nextWardrobeSort switches to the next sort order and saves it
+func nextWardrobeSort() {
+	next := wardrobeSorts[(slices.Index(wardrobeSorts, wardrobeSort())+1)%len(wardrobeSorts)]
+
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+	currentSave().WardrobeSort = next
+}
+
+// WearLastOutfit puts back on the outfit remembered from the last session, without a reaction.
+// Call it once the clothes are loaded, before the app runs.
+func WearLastOutfit(waifuArt *tview.TextView, head string, currentBody *string) {
+	saveMutex.Lock()
+	name := currentSave().LastOutfit
+	saveMutex.Unlock()
+
+	if name != "" {
+		wearOutfit(waifuArt, head, currentBody, name)
+	}
+}
+
+// ==============================
+// WARDROBE ENTRIES
+// ==============================
+
+// wardrobeEntry is a line of the wardrobe: an outfit, or a category header when Name is ""
+type wardrobeEntry struct {
+	Name  string
+	Label string
+	Data  string
+}
+
+// outfitCategory returns the clothes subdirectory of an outfit, "" at the top
+func outfitCategory(name string) string {
+	if dir := path.Dir(name); dir != "." {
+		return dir
+	}
+	return ""
+}
+
+// wardrobeEntries groups the wearable outfits: favorites first, then the top of clothes/,
+// then each subdirectory, every group sorted by `sortBy`
+func wardrobeEntries(sortBy string) []wardrobeEntry {
+	saveMutex.Lock()
+	favorites := slices.Clone(currentSave().FavoriteOutfits)
+	wears := map[string]int{}
+	for k, v := range currentSave().OutfitWears {
+		wears[k] = v
+	}
+	saveMutex.Unlock()
+
+	const favoritesGroup = "\x00favorites" // can't be a directory name
+	groups := map[string][]wardrobeEntry{}
+	for _, item := range clothesCache {
+		if !OutfitUnlocked(item.Name) {
+			continue
+		}
+		group, mark := outfitCategory(item.Name), "-"
+		if slices.Contains(favorites, item.Name) {
+			group, mark = favoritesGroup, "★"
+		}
+		label := mark + " " + tview.Escape(path.Base(item.Name))
+		if item.Name == CurrentOutfit {
+			label += " ✓"
+		}
+		groups[group] = append(groups[group], wardrobeEntry{item.Name, label, item.Data})
+	}
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == favoritesGroup) != (names[j] == favoritesGroup) {
+			return names[i] == favoritesGroup
+		}
+		return names[i] < names[j]
+	})
+
+	var entries []wardrobeEntry
+	for _, group := range names {
+		items := groups[group]
+		sort.SliceStable(items, func(i, j int) bool {
+			a, b := items[i].Name, items[j].Name
+			if sortBy == "worn" && wears[a] != wears[b] {
+				return wears[a] > wears[b]
+			}
+			return path.Base(a) < path.Base(b)
+		})
+
+		header := group
+		switch group {
+		case favoritesGroup:
+			header = "Favorites"
+		case "":
+			header = "Outfits"
+		}
+		entries = append(entries, wardrobeEntry{Label: "[::b]── " + tview.Escape(header) + " ──[::-]"})
+		entries = append(entries, items...)
+	}
+	return entries
+}
+
+// ==============================
+// WARDROBE VIEW
+// ==============================
+
+// DressUp opens the wardrobe in place of the action space. Moving through it previews
+// the outfit on the avatar, Enter puts it on and Esc puts back the one she was wearing.
+// "*" marks a favorite and "s" changes the sort order.
+func DressUp(
+	app *tview.Application,
+	grid *tview.Grid,
+	actionSpace *tview.List,
+	waifuArt, chatBox *tview.TextView,
+	head, waifuName string,
+	currentBody *string,
+) {
+	if len(clothesCache) == 0 {
+		showChatMessage(chatBox, "No clothes found!")
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	ApplyListPalette(cachedPalette, list) // Safe: the error could have occurred during the initialization
+	list.SetBorder(true).SetTitleAlign(tview.AlignCenter)
+
+	// The grid stays as it is until the wardrobe closes, so no other menu can leave the preview on
+	LockGridChanges = true
+
+	wearing := *currentBody
+	preview := func(body string) {
+		*currentBody = body
+		waifuArt.SetText(head + "\n" + body)
+	}
+	// putBack shows the outfit actually worn, which the command palette may have changed meanwhile
+	putBack := func() {
+		for _, item := range clothesCache {
+			if item.Name == CurrentOutfit {
+				preview(item.Data)
+				return
+			}
+		}
+		preview(wearing)
+	}
+
+	var entries []wardrobeEntry
+	var building bool
+	last := 0
+
+	// fill lists the entries again and keeps the cursor on `selected`
+	fill := func(selected string) {
+		building = true
+		defer func() { building = false }()
+
+		sortBy := wardrobeSort()
+		list.SetTitle("| Wardrobe · " + wardrobeSortLabels[sortBy] + " · * fav |")
+		entries = wardrobeEntries(sortBy)
+		list.Clear()
+		last = 1
+		for i, e := range entries {
+			entry := e
+			if entry.Name == selected {
+				last = i
+			}
+			list.AddItem(entry.Label, "", 0, func() {
+				if entry.Name == "" {
+					return
+				}
+				if !ChangeOutfit(waifuArt, chatBox, head, waifuName, currentBody, entry.Name) {
+					preview(wearing)
+				}
+				closeDressUp(app, grid, list, actionSpace)
+			})
+		}
+		list.SetCurrentItem(last)
+	}
+
+	list.SetChangedFunc(func(index int, _, _ string, _ rune) {
+		if building || index >= len(entries) {
+			return
+		}
+		// Headers can't be selected: the cursor goes over them in the direction it moved
+		if entries[index].Name == "" {
+			if index > last || index == 0 {
+				list.SetCurrentItem(index + 1)
+			} else {
+				list.SetCurrentItem(index - 1)
+			}
+			return
+		}
+		last = index
+		preview(entries[index].Data)
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		selected := entries[list.GetCurrentItem()].Name
+		switch event.Rune() {
+		case '*':
+			toggleFavoriteOutfit(selected)
+		case 's':
+			nextWardrobeSort()
+		default:
+			return event
+		}
+		fill(selected)
+		return nil
+	})
+
+	list.SetDoneFunc(func() {
+		putBack()
+		closeDressUp(app, grid, list, actionSpace)
+	})
+
+	fill(CurrentOutfit)
+	if len(entries) == 0 {
+		LockGridChanges = false
+		showChatMessage(chatBox, "No clothes found!")
+		return
+	}
+	preview(entries[last].Data)
+
+	// Swap in the wardrobe
+	grid.RemoveItem(actionSpace)
+	grid.AddItem(list, 0, 0, 1, 1, 0, 0, true)
+	app.SetFocus(list)
+}
+
+// closeDressUp restores the actionSpace and unlocks the grid
+func closeDressUp(
+	app *tview.Application,
+	grid *tview.Grid,
+	list, actionSpace *tview.List,
+) {
+	LockGridChanges = false
+
+	grid.RemoveItem(list)
+	grid.AddItem(actionSpace, 0, 0, 1, 1, 0, 0, true)
+	app.SetFocus(actionSpace)
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"fmt"
+	"path"
+	"sync"
+	"time"
+	"math/rand"
+
+	"github.com/rivo/tview"
+)
+
+// ==============================
+// WISHES
+// ==============================
+
+// wish is a gift or an outfit she asked for
+type wish struct {
+	Kind  string // "gift" or "outfit"
+	Name  string
+	Until time.Time
+}
+
+var (
+	wishMutex   sync.Mutex
+	currentWish *wish
+	wishBonus   Wishes // rewards of the running wishes, set by StartWishes
+)
+
+// Line added to her reaction when a wish comes true
+const wishGranted = " It's just what I wished for! ♥"
+
+// FulfillWish grants the bonus if the gift or outfit is the one she wished for
+func FulfillWish(kind, name string) bool {
+	wishMutex.Lock()
+	w := currentWish
+	if w == nil || w.Kind != kind || w.Name != name {
+		wishMutex.Unlock()
+		return false
+	}
+	currentWish = nil
+	wishMutex.Unlock()
+
+	SetStatusItem("wish", "")
+	IncreaseHappiness(wishBonus.Happiness)
+	AddXP(wishBonus.XP)
+	TrackStat("wishes", 1)
+	return true
+}
+
+// pickWish chooses a gift she doesn't dislike or another outfit than the current one
+func pickWish() (string, string, bool) {
+	var outfits []string
+	worn := wornOutfit()
+	for _, name := range OutfitNames() {
+		if name != worn {
+			outfits = append(outfits, name)
+		}
+	}
+	var gifts []Gift
+	for _, g := range AvailableGifts() {
+		if g.Preference != "dislike" {
+			gifts = append(gifts, g)
+		}
+	}
+
+	switch {
+	case len(gifts) > 0 && (len(outfits) == 0 || rand.Intn(2) == 0):
+		return "gift", gifts[rand.Intn(len(gifts))].Name, true
+	case len(outfits) > 0:
+		return "outfit", outfits[rand.Intn(len(outfits))], true
+	}
+	return "", "", false
+}
+
+var wishLines = map[string][]string{
+	"gift": {
+		"I'd love a %s... if you have one.",
+		"Hey {user}, could I get a %s? Pretty please?",
+	},
+	"outfit": {
+		"Can I wear the %s? I miss it.",
+		"I feel like wearing the %s today~",
+	},
+}
+
+// StartWishes makes her wish for a gift or an outfit every "every" minutes on average.
+// A wish shows next to the happiness bar until it is fulfilled or "timeout" minutes pass.
+func StartWishes(chatBox *tview.TextView, waifuName string) {
+	cfg := DefaultSettings().Wishes
+	if s, err := LoadSettings(); err == nil {
+		cfg = s.Wishes
+	}
+	if !cfg.Enabled || cfg.Every <= 0 || cfg.Timeout <= 0 {
+		return
+	}
+	wishBonus = cfg
+
+	// Somewhere between half and one and a half "every"
+	nextWish := func(now time.Time) time.Time {
+		spread := time.Duration(cfg.Every) * time.Minute
+		return now.Add(spread/2 + time.Duration(rand.Int63n(int64(spread))))
+	}
+	next := nextWish(time.Now())
+
+	say := func(line string) {
+		text := waifuName + ": " + RenderTemplate(line, CurrentContext())
+		UIEventsChan <- func() {
+			SetChat(chatBox, text)
+		}
+	}
+
+	OnTick(func(now time.Time) {
+		// Only the wish changes under the lock: the UI goroutine takes it too (FulfillWish),
+		// so sending on UIEventsChan while holding it could deadlock
+		var expired, made *wish
+		wishMutex.Lock()
+		if w := currentWish; w != nil {
+			if !now.Before(w.Until) {
+				expired, currentWish = w, nil
+				next = nextWish(now)
+			}
+		} else if !now.Before(next) {
+			next = nextWish(now)
+			if kind, name, ok := pickWish(); ok {
+				made = &wish{Kind: kind, Name: name, Until: now.Add(time.Duration(cfg.Timeout) * time.Minute)}
+				currentWish = made
+			}
+		}
+		wishMutex.Unlock()
+
+		switch {
+		case expired != nil:
+			SetStatusItem("wish", "")
+			DecreaseHappiness(cfg.Penalty)
+			say("Never mind the " + path.Base(expired.Name) + "... it's fine.")
+		case made != nil:
+			SetStatusItem("wish", "♡ "+path.Base(made.Name))
+			lines := wishLines[made.Kind]
+			say(fmt.Sprintf(lines[rand.Intn(len(lines))], path.Base(made.Name)))
+		}
+	})
+}